@@ -6,7 +6,11 @@ import (
 	"net/http"
 
 	"github.com/sisoputnfrba/tp-golang/cpu/cicloInstruccion"
+	"github.com/sisoputnfrba/tp-golang/cpu/debugger"
 	"github.com/sisoputnfrba/tp-golang/cpu/globals"
+	"github.com/sisoputnfrba/tp-golang/cpu/icache"
+	"github.com/sisoputnfrba/tp-golang/cpu/traza"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/generics"
 	"github.com/sisoputnfrba/tp-golang/utils/pcb"
 )
@@ -26,7 +30,15 @@ func PCB_recv(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// La ráfaga es una actividad para el reloj virtual: no avanza mientras CPU ejecuta
+	clock.Busy()
+	defer clock.Idle()
+
 	globals.CurrentJob = &received_pcb
+	globals.CurrentJob.DispatchInstructions = 0
+	globals.CurrentJob.Syscall = nil
+	globals.CurrentJob.Exception = ""
+	cicloInstruccion.NormalizarRegistros(globals.CurrentJob)
 
 	for {
 		globals.EvictionMutex.Lock()
@@ -35,20 +47,33 @@ func PCB_recv(w http.ResponseWriter, r *http.Request) {
 			break }
 		globals.EvictionMutex.Unlock()
 		
-		if (globals.MemDelay > int(globals.CurrentJob.Quantum)) {
-			globals.CurrentJob.EvictionReason = "TIMEOUT"
+		if (globals.MemDelay > int(globals.CurrentJob.TimeSlice())) {
+			globals.CurrentJob.EvictionReason = pcb.ReasonTimeout
 			pcb.EvictionFlag = true
 		}
+		debugger.Esperar(globals.CurrentJob)
+		traza.Comenzar(globals.CurrentJob.PID, globals.CurrentJob.PC, cicloInstruccion.Registros(globals.CurrentJob))
 		cicloInstruccion.DecodeAndExecute(globals.CurrentJob)
-		
-		fmt.Println("Los registros de la cpu son", globals.CurrentJob.CPU_reg)
+		traza.Terminar(cicloInstruccion.Registros(globals.CurrentJob))
+		globals.CurrentJob.DispatchInstructions++
 
+		// Si agotó su límite de instrucciones se desaloja para que kernel lo finalice
+		if executed := globals.CurrentJob.Instructions + globals.CurrentJob.DispatchInstructions; globals.CurrentJob.MaxInstructions > 0 && executed >= globals.CurrentJob.MaxInstructions && !pcb.EvictionFlag {
+			globals.CurrentJob.EvictionReason = pcb.ReasonLimitExceeded
+			pcb.EvictionFlag = true
+		}
 	}
 
 	fmt.Println("CPU - El motivo de la interrupción es: ", globals.CurrentJob.EvictionReason)
 	//fmt.Println("ABER MOSTRAMELON: ", pcb.EvictionFlag) // * Se recordará su contribución a la ciencia
 	pcb.EvictionFlag = false
 
+	// Un proceso que termina no vuelve a ejecutar: sus instrucciones ya no sirven en la caché
+	if globals.CurrentJob.EvictionReason.Terminates() {
+		icache.Invalidar(globals.CurrentJob.PID)
+		debugger.Olvidar(globals.CurrentJob.PID)
+	}
+
 	jsonResp, err := json.Marshal(globals.CurrentJob)
 	if err != nil {
 		http.Error((w), "Failed to encode PCB response", http.StatusInternalServerError)
@@ -80,7 +105,7 @@ func HandleInterruption(w http.ResponseWriter, r *http.Request) {
 	fmt.Println("PID Requerido: ", request.Pid)
 	fmt.Println("PID Actual: ", globals.CurrentJob.PID)
 	
-	if _, ok := globals.EvictionReasons[globals.CurrentJob.EvictionReason]; !ok && request.Pid == globals.CurrentJob.PID && (globals.CurrentJob.Executions == request.ExecutionNumber || request.ExecutionNumber == -1) {
+	if !globals.CurrentJob.EvictionReason.SelfEviction() && request.Pid == globals.CurrentJob.PID && (globals.CurrentJob.Executions == request.ExecutionNumber || request.ExecutionNumber == -1) {
 		fmt.Println("Se acepta interrumpir PID: ", request.Pid)
 		fmt.Printf("Motivo de interrupción: %s\n", request.InterruptionReason)
 
@@ -90,16 +115,30 @@ func HandleInterruption(w http.ResponseWriter, r *http.Request) {
 
 		switch request.InterruptionReason {
 		case "QUANTUM":
-			globals.CurrentJob.EvictionReason = "TIMEOUT"
+			globals.CurrentJob.EvictionReason = pcb.ReasonTimeout
 
 		case "DELETE":
-			globals.CurrentJob.EvictionReason = "INTERRUPTED_BY_USER"
+			globals.CurrentJob.EvictionReason = pcb.ReasonInterruptedByUser
+
+		case "LIMIT":
+			globals.CurrentJob.EvictionReason = pcb.ReasonLimitExceeded
+
+		case "PAUSE":
+			globals.CurrentJob.EvictionReason = pcb.ReasonPaused
 		}
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
+/**
+ * Health: Responde al health check de kernel
+ */
+func Health(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
 func RequestMemoryDelay() {
 	url := fmt.Sprintf("http://%s:%d/delay", globals.Configcpu.IP_memory, globals.Configcpu.Port_memory)
 
@@ -0,0 +1,210 @@
+package cicloInstruccion
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+)
+
+// Dividir (o calcular el resto) por cero
+var ErrDivisionPorCero = errors.New("división por cero")
+
+/**
+ * Operar: Aplica una operación aritmética o lógica entre dos operandos y deja el resultado en el destino.
+ * Se opera en el ancho del primer registro que aparezca (4 bytes si no hay registros); el origen se trunca a ese ancho,
+ * igual que el resultado. Actualiza FLAGS.
+
+ * @param currentPCB: proceso en ejecución
+ * @param operacion: SUM, SUB, MUL, DIV, MOD, AND, OR, XOR, SHL, SHR o CMP (que solo actualiza FLAGS)
+ * @param destino: registro u operando de memoria
+ * @param origen: registro, inmediato u operando de memoria
+ * @return error: ErrDivisionPorCero, o la operación desconocida (en ambos casos el destino no se modifica)
+**/
+func Operar(currentPCB *pcb.T_PCB, operacion string, destino T_Operando, origen T_Operando) error {
+	ancho := AnchoEntre(destino, origen)
+	a, b := LeerOperando(currentPCB, destino, ancho), LeerOperando(currentPCB, origen, ancho)
+
+	var resultado uint32
+	var flags uint8
+	if ancho == 4 {
+		r, err := operar(operacion, a, b)
+		if err != nil {
+			return err
+		}
+		resultado, flags = r, calcularFlags(operacion, a, b, r)
+	} else {
+		r, err := operar(operacion, uint8(a), uint8(b))
+		if err != nil {
+			return err
+		}
+		resultado, flags = uint32(r), calcularFlags(operacion, uint8(a), uint8(b), r)
+	}
+
+	currentPCB.CPU_reg["FLAGS"] = flags
+	if operacion != "CMP" {
+		EscribirOperando(currentPCB, destino, ancho, resultado)
+	}
+	return nil
+}
+
+/**
+ * DesalojarSiFalla: Desaloja al proceso si la operación falló: DIVISION_BY_ZERO, o INVALID_INSTRUCTION si la operación no existe
+
+ * @param currentPCB: proceso en ejecución
+ * @param err: resultado de Operar
+**/
+func DesalojarSiFalla(currentPCB *pcb.T_PCB, err error) {
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrDivisionPorCero):
+		fmt.Print("División por cero\n")
+		currentPCB.EvictionReason = pcb.ReasonDivisionByZero
+		pcb.EvictionFlag = true
+	default:
+		Excepcion(currentPCB, pcb.ReasonInvalidInstruction, err.Error())
+	}
+}
+
+/**
+ * Negar: Invierte los bits de un registro u operando de memoria, respetando su ancho (NOT)
+
+ * @param currentPCB: proceso en ejecución
+ * @param destino: registro u operando de memoria
+**/
+func Negar(currentPCB *pcb.T_PCB, destino T_Operando) {
+	ancho := AnchoEntre(destino)
+	valor := LeerOperando(currentPCB, destino, ancho)
+
+	var resultado uint32
+	if ancho == 4 {
+		resultado = ^valor
+		currentPCB.CPU_reg["FLAGS"] = calcularFlags("NOT", 0, 0, resultado)
+	} else {
+		r := ^uint8(valor)
+		resultado = uint32(r)
+		currentPCB.CPU_reg["FLAGS"] = calcularFlags("NOT", 0, 0, r)
+	}
+	EscribirOperando(currentPCB, destino, ancho, resultado)
+}
+
+// operar: Resuelve la operación en el ancho T, que trunca el resultado igual que la suma y la resta
+func operar[T Uint](operacion string, a T, b T) (T, error) {
+	switch operacion {
+	case "SUM":
+		return a + b, nil
+	case "SUB", "CMP":
+		return a - b, nil
+	case "MUL":
+		return a * b, nil
+	case "DIV":
+		if b == 0 {
+			return 0, ErrDivisionPorCero
+		}
+		return a / b, nil
+	case "MOD":
+		if b == 0 {
+			return 0, ErrDivisionPorCero
+		}
+		return a % b, nil
+	case "AND":
+		return a & b, nil
+	case "OR":
+		return a | b, nil
+	case "XOR":
+		return a ^ b, nil
+	case "SHL":
+		return a << b, nil
+	case "SHR":
+		return a >> b, nil
+	}
+	return a, fmt.Errorf("operación %q desconocida", operacion)
+}
+
+// calcularFlags: Calcula FLAGS a partir de los operandos y el resultado, en el ancho T
+func calcularFlags[T Uint](operacion string, a T, b T, resultado T) uint8 {
+	bitAlto := ^T(0) ^ (^T(0) >> 1)
+	ancho := T(0)
+	for bit := ^T(0); bit != 0; bit >>= 1 {
+		ancho++
+	}
+
+	var flags uint8
+	if resultado == 0 {
+		flags |= pcb.FlagZero
+	}
+	if resultado&bitAlto != 0 {
+		flags |= pcb.FlagSign
+	}
+
+	switch operacion {
+	case "SUM":
+		if resultado < a {
+			flags |= pcb.FlagCarry
+		}
+		if (a^resultado)&(b^resultado)&bitAlto != 0 {
+			flags |= pcb.FlagOverflow
+		}
+	case "SUB", "CMP":
+		if a < b {
+			flags |= pcb.FlagCarry
+		}
+		if (a^b)&(a^resultado)&bitAlto != 0 {
+			flags |= pcb.FlagOverflow
+		}
+	case "MUL":
+		if a != 0 && resultado/a != b {
+			flags |= pcb.FlagCarry | pcb.FlagOverflow
+		}
+	case "SHL":
+		if b > 0 && b <= ancho && (a>>(ancho-b))&1 != 0 {
+			flags |= pcb.FlagCarry
+		}
+	case "SHR":
+		if b > 0 && b <= ancho && (a>>(b-1))&1 != 0 {
+			flags |= pcb.FlagCarry
+		}
+	}
+	return flags
+}
+
+/**
+ * CondicionDeSalto: Evalúa la condición de un salto en base a FLAGS. Las comparaciones son sin signo, como los registros.
+
+ * @param currentPCB: proceso en ejecución
+ * @param salto: JMP, JZ, JNZ, JG, JL, JGE o JLE
+ * @return bool: true si se debe saltar
+**/
+func CondicionDeSalto(currentPCB *pcb.T_PCB, salto string) bool {
+	flags := uint8(LeerRegistro(currentPCB, "FLAGS"))
+	cero := flags&pcb.FlagZero != 0
+	menor := flags&pcb.FlagCarry != 0
+
+	switch salto {
+	case "JMP":
+		return true
+	case "JZ":
+		return cero
+	case "JNZ":
+		return !cero
+	case "JG":
+		return !menor && !cero
+	case "JL":
+		return menor
+	case "JGE":
+		return !menor
+	case "JLE":
+		return menor || cero
+	}
+	return false
+}
+
+/**
+ * Saltar: Mueve el PC a la instrucción indicada (número o registro)
+
+ * @param currentPCB: proceso en ejecución
+ * @param destino: instrucción de destino
+**/
+func Saltar(currentPCB *pcb.T_PCB, destino string) {
+	EscribirRegistro(currentPCB, "PC", ValorOperando(currentPCB, destino))
+}
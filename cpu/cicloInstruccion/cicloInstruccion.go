@@ -3,21 +3,24 @@ package cicloInstruccion
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
-	"math/big"
 	"net/http"
-	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sisoputnfrba/tp-golang/cpu/globals"
+	"github.com/sisoputnfrba/tp-golang/cpu/icache"
+	"github.com/sisoputnfrba/tp-golang/cpu/traza"
 
 	mmu "github.com/sisoputnfrba/tp-golang/cpu/mmu"
 
 	solicitudesmemoria "github.com/sisoputnfrba/tp-golang/cpu/solicitudesMemoria"
 	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+	"github.com/sisoputnfrba/tp-golang/utils/validator"
 )
 
 /**
@@ -42,7 +45,22 @@ func Delimitador(instActual string) []string {
 	return instruccionDecodificada
 }
 
-func Fetch(currentPCB *pcb.T_PCB) string {
+// ErrFueraDelScript: memoria no tiene una instrucción en ese PC, por ejemplo después de saltar al valor de un registro
+var ErrFueraDelScript = errors.New("no hay instrucción en ese program counter")
+
+// Reintentos de FETCH mientras memoria no responde, antes de devolver el proceso a kernel
+const (
+	IntentosFetch 		= 3
+	EsperaFetchInicial 	= 100 * time.Millisecond
+)
+
+/**
+ * Fetch: Obtiene de memoria (o de la caché) la instrucción apuntada por el PC
+
+ * @param currentPCB: proceso en ejecución
+ * @return instrucción, origen en el script y error: ErrFueraDelScript si el PC no existe, o la falla de comunicación con memoria
+**/
+func Fetch(currentPCB *pcb.T_PCB) (string, string, error) {
 	// CPU pasa a memoria el PID y el PC, y memoria le devuelve la instrucción
 	// (después de identificar en el diccionario la key: PID,
 	// va a buscar en la lista de instrucciones de ese proceso, la instrucción en la posición
@@ -53,12 +71,46 @@ func Fetch(currentPCB *pcb.T_PCB) string {
 	pc := currentPCB.PC
 	globals.PCBMutex.Unlock()
 
+	// Con la caché habilitada las instrucciones se traen de a bloques y memoria cobra su retardo una vez por bloque
+	if icache.Habilitada() {
+		if entrada, err := icache.Buscar(pid, pc); err == nil {
+			logFetch(pid, pc, entrada.Origen)
+			return entrada.Instruccion, entrada.Origen, nil
+		} else {
+			log.Printf("PID: %d - No se pudo obtener la instrucción de la caché: %v", pid, err)
+		}
+	}
+
+	// Una caída breve de memoria no es culpa del proceso: se reintenta, y solo un PC inexistente corta enseguida
+	var err error
+	espera := EsperaFetchInicial
+	for intento := 1; intento <= IntentosFetch; intento++ {
+		var instruccion, origen string
+		instruccion, origen, err = pedirInstruccion(pid, pc)
+		if err == nil {
+			logFetch(pid, pc, origen)
+			return instruccion, origen, nil
+		}
+		if errors.Is(err, ErrFueraDelScript) {
+			return "", "", err
+		}
+		log.Printf("PID: %d - FETCH - Program Counter: %d - Intento %d fallido: %v", pid, pc, intento, err)
+		if intento < IntentosFetch {
+			time.Sleep(espera)
+			espera *= 2
+		}
+	}
+	return "", "", err
+}
+
+// pedirInstruccion: Un único pedido de la instrucción a memoria
+func pedirInstruccion(pid uint32, pc uint32) (string, string, error) {
 	cliente := &http.Client{}
 	url := fmt.Sprintf("http://%s:%d/instrucciones", globals.Configcpu.IP_memory, globals.Configcpu.Port_memory)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		fmt.Print("Error al crear el request")
+		return "", "", err
 	}
 	q := req.URL.Query()
 	q.Add("pid", strconv.Itoa(int(pid)))
@@ -68,31 +120,104 @@ func Fetch(currentPCB *pcb.T_PCB) string {
 	req.Header.Set("Content-Type", "application/json")
 	respuesta, err := cliente.Do(req)
 	if err != nil {
-		fmt.Print("Error al hacer el request")
-	}
-
-	if respuesta.StatusCode != http.StatusOK {
-		fmt.Print("Error en el estado de la respuesta")
+		return "", "", fmt.Errorf("no se pudo pedir la instrucción %d a memoria: %w", pc, err)
 	}
+	defer respuesta.Body.Close()
 
 	instruccion, err := io.ReadAll(respuesta.Body)
 	if err != nil {
-		fmt.Print("Error al leer el cuerpo de la respuesta")
+		return "", "", fmt.Errorf("no se pudo leer la instrucción %d: %w", pc, err)
 	}
 
-	instruccion1 := string(instruccion)
+	switch respuesta.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", "", fmt.Errorf("%w (%d): %s", ErrFueraDelScript, pc, strings.TrimSpace(string(instruccion)))
+	default:
+		return "", "", fmt.Errorf("memoria no devolvió la instrucción %d: %s", pc, strings.TrimSpace(string(instruccion)))
+	}
 
-	log.Printf("PID: %d - FETCH - Program Counter: %d", pid, pc)
+	return string(instruccion), respuesta.Header.Get("X-Source"), nil
+}
 
-	return instruccion1
+// logFetch: Log obligatorio de FETCH, con el origen de la instrucción en el script si se conoce
+func logFetch(pid uint32, pc uint32, origen string) {
+	if origen != "" {
+		log.Printf("PID: %d - FETCH - Program Counter: %d - %s", pid, pc, origen)
+	} else {
+		log.Printf("PID: %d - FETCH - Program Counter: %d", pid, pc)
+	}
 }
 
 func DecodeAndExecute(currentPCB *pcb.T_PCB) {
-	instActual := Fetch(currentPCB)
+	var instActual, origen string
+
+	// Una instrucción que hace fallar a CPU al ejecutarla no puede tirar abajo el handler: se convierte en una excepción del proceso
+	defer func() {
+		if falla := recover(); falla != nil {
+			mensaje := fmt.Sprint(falla)
+			if instActual != "" {
+				mensaje = fmt.Sprintf("%q: %v", instActual, falla)
+			}
+			Excepcion(currentPCB, pcb.ReasonInvalidInstruction, mensaje)
+		}
+		if currentPCB.Exception != "" && origen != "" {
+			currentPCB.Exception = origen + " - " + currentPCB.Exception
+		}
+	}()
+
+	var err error
+	instActual, origen, err = Fetch(currentPCB)
+	if errors.Is(err, ErrFueraDelScript) {
+		Excepcion(currentPCB, pcb.ReasonInvalidInstruction, err.Error())
+		return
+	}
+	if err != nil {
+		// El proceso vuelve a READY sin avanzar el PC: la instrucción se vuelve a buscar cuando kernel lo replanifique
+		log.Printf("PID: %d - Desalojado porque memoria no responde: %v", currentPCB.PID, err)
+		currentPCB.EvictionReason = pcb.ReasonMemoryUnavailable
+		pcb.EvictionFlag = true
+		return
+	}
+	Ejecutar(currentPCB, instActual)
+}
+
+/**
+ * Excepcion: Desaloja al proceso por un error de la instrucción en curso. Kernel lo finaliza y loguea el detalle.
+
+ * @param currentPCB: proceso en ejecución
+ * @param motivo: INVALID_INSTRUCTION o INVALID_REGISTER
+ * @param mensaje: detalle del error
+**/
+func Excepcion(currentPCB *pcb.T_PCB, motivo pcb.EvictionReason, mensaje string) {
+	log.Printf("PID: %d - EXCEPCIÓN %s - Program Counter: %d - %s", currentPCB.PID, motivo, currentPCB.PC, mensaje)
+	currentPCB.EvictionReason = motivo
+	currentPCB.Exception = mensaje
+	pcb.EvictionFlag = true
+}
+
+/**
+ * Ejecutar: Decodifica y ejecuta una instrucción ya obtenida. Separado de Fetch para poder reproducir trazas sin memoria.
+
+ * @param currentPCB: proceso en ejecución
+ * @param instActual: instrucción tal como la devuelve memoria
+**/
+func Ejecutar(currentPCB *pcb.T_PCB, instActual string) {
 	instruccionDecodificada := Delimitador(instActual)
+	traza.Instruccion(instruccionDecodificada[0], instruccionDecodificada[1:])
+
+	// Se valida antes de tocar el proceso: el PC queda en la instrucción que falló
+	if falla := validator.CheckInstruction(instruccionDecodificada); falla != nil {
+		motivo := pcb.ReasonInvalidInstruction
+		if falla.Kind == validator.FaultRegister {
+			motivo = pcb.ReasonInvalidRegister
+		}
+		Excepcion(currentPCB, motivo, fmt.Sprintf("%q: %s", instActual, falla))
+		return
+	}
 
 	if instruccionDecodificada[0] == "EXIT" {
-		currentPCB.EvictionReason = "EXIT"
+		currentPCB.EvictionReason = pcb.ReasonExit
 		pcb.EvictionFlag = true
 
 		log.Printf("PID: %d - Ejecutando: %s", currentPCB.PID, instruccionDecodificada[0])
@@ -100,6 +225,19 @@ func DecodeAndExecute(currentPCB *pcb.T_PCB) {
 		log.Printf("PID: %d - Ejecutando: %s - %s", currentPCB.PID, instruccionDecodificada[0], instruccionDecodificada[1:])
 	}
 
+	// Las instrucciones aritméticas y de movimiento aceptan registros, inmediatos (#5) y memoria ([EBX], [EBX+4]).
+	// Sus operandos se decodifican una sola vez, acá.
+	var operandos []T_Operando
+	if _, conOperandos := InstruccionesConOperandos[instruccionDecodificada[0]]; conOperandos {
+		var err error
+		operandos, err = DecodificarOperandos(instruccionDecodificada[0], OperandosDe(instruccionDecodificada))
+		if err != nil {
+			Excepcion(currentPCB, pcb.ReasonInvalidInstruction, fmt.Sprintf("%q: %v", instActual, err))
+			return
+		}
+	}
+
+	// El PC avanza recién con los operandos decodificados, así una excepción reporta la instrucción que falló
 	currentPCB.PC++
 	currentPCB.CPU_reg["PC"] = uint32(currentPCB.PC)
 
@@ -108,25 +246,20 @@ func DecodeAndExecute(currentPCB *pcb.T_PCB) {
 		cond, err := HallarInterfaz(instruccionDecodificada[1], "DIALFS")
 		if err != nil {
 			fmt.Print("La interfaz no existe o no acepta operaciones de IO Genéricas")
-			currentPCB.EvictionReason = "EXIT"
+			currentPCB.EvictionReason = pcb.ReasonExit
 		} else {
 			nombre_archivo := instruccionDecodificada[2]
 			if cond {
 
-				var fsCreateBody = struct {
-					InterfaceName string
-					FileName      string
-					Operation     string
-				}{
-					InterfaceName: instruccionDecodificada[1],
-					FileName:      nombre_archivo,
-					Operation:     "CREATE",
+				currentPCB.Syscall = &pcb.T_Syscall{
+					Name:      instruccionDecodificada[0],
+					Interface: instruccionDecodificada[1],
+					FileName:  nombre_archivo,
+					Operation: "CREATE",
 				}
-
-				SendIOData(fsCreateBody, "iodata-dialfs")
-				currentPCB.EvictionReason = "BLOCKED_IO_DIALFS"
+				currentPCB.EvictionReason = pcb.ReasonIODialFS
 			} else {
-				currentPCB.EvictionReason = "EXIT"
+				currentPCB.EvictionReason = pcb.ReasonExit
 			}
 		}
 		pcb.EvictionFlag = true
@@ -135,25 +268,20 @@ func DecodeAndExecute(currentPCB *pcb.T_PCB) {
 		cond, err := HallarInterfaz(instruccionDecodificada[1], "DIALFS")
 		if err != nil {
 			fmt.Print("La interfaz no existe o no acepta operaciones de IO Genéricas")
-			currentPCB.EvictionReason = "EXIT"
+			currentPCB.EvictionReason = pcb.ReasonExit
 		} else {
 			nombre_archivo := instruccionDecodificada[2]
 			if cond {
 
-				var fsCreateBody = struct {
-					InterfaceName string
-					FileName      string
-					Operation     string
-				}{
-					InterfaceName: instruccionDecodificada[1],
-					FileName:      nombre_archivo,
-					Operation:     "DELETE",
+				currentPCB.Syscall = &pcb.T_Syscall{
+					Name:      instruccionDecodificada[0],
+					Interface: instruccionDecodificada[1],
+					FileName:  nombre_archivo,
+					Operation: "DELETE",
 				}
-
-				SendIOData(fsCreateBody, "iodata-dialfs")
-				currentPCB.EvictionReason = "BLOCKED_IO_DIALFS"
+				currentPCB.EvictionReason = pcb.ReasonIODialFS
 			} else {
-				currentPCB.EvictionReason = "EXIT"
+				currentPCB.EvictionReason = pcb.ReasonExit
 			}
 		}
 		pcb.EvictionFlag = true
@@ -167,39 +295,24 @@ func DecodeAndExecute(currentPCB *pcb.T_PCB) {
 		cond, err := HallarInterfaz(instruccionDecodificada[1], "DIALFS")
 		if err != nil {
 			fmt.Print("La interfaz no existe o no acepta operaciones de IO Genéricas")
-			currentPCB.EvictionReason = "EXIT"
+			currentPCB.EvictionReason = pcb.ReasonExit
 		} else {
 			nombre_archivo := instruccionDecodificada[2]
-			tamanio_archivo := currentPCB.CPU_reg[instruccionDecodificada[3]]
-			var tamanioEnInt int
-
-			tipoActualReg2 := reflect.TypeOf(currentPCB.CPU_reg[instruccionDecodificada[3]]).String()
-
-			if tipoActualReg2 == "uint32" {
-				tamanioEnInt = int(Convertir[uint32](tipoActualReg2, tamanio_archivo))
-			} else {
-				tamanioEnInt = int(Convertir[uint8](tipoActualReg2, tamanio_archivo))
-			}
+			tamanioEnInt := int(LeerValor(currentPCB, operandos[0]))
 
 			if cond {
 
-				var fsCreateBody = struct {
-					InterfaceName string
-					FileName      string
-					Size          int
-					Operation     string
-				}{
-					InterfaceName: instruccionDecodificada[1],
-					FileName:      nombre_archivo,
-					Size:          tamanioEnInt,
-					Operation:     "TRUNCATE",
+				currentPCB.Syscall = &pcb.T_Syscall{
+					Name:      instruccionDecodificada[0],
+					Interface: instruccionDecodificada[1],
+					FileName:  nombre_archivo,
+					Size:      tamanioEnInt,
+					Operation: "TRUNCATE",
 				}
-
-				SendIOData(fsCreateBody, "iodata-dialfs")
-				currentPCB.EvictionReason = "BLOCKED_IO_DIALFS"
+				currentPCB.EvictionReason = pcb.ReasonIODialFS
 
 			} else {
-				currentPCB.EvictionReason = "EXIT"
+				currentPCB.EvictionReason = pcb.ReasonExit
 			}
 		}
 		pcb.EvictionFlag = true
@@ -212,66 +325,29 @@ func DecodeAndExecute(currentPCB *pcb.T_PCB) {
 		cond, err := HallarInterfaz(instruccionDecodificada[1], "DIALFS")
 		if err != nil {
 			fmt.Print("La interfaz no existe o no acepta operaciones de IO Genéricas")
-			currentPCB.EvictionReason = "EXIT"
+			currentPCB.EvictionReason = pcb.ReasonExit
 		} else {
 			nombre_archivo := instruccionDecodificada[2]
-			direccion := currentPCB.CPU_reg[instruccionDecodificada[3]]
-			var direccionEnInt int
-
-			tipoActualRegDireccion := reflect.TypeOf(currentPCB.CPU_reg[instruccionDecodificada[3]]).String()
-
-			if tipoActualRegDireccion == "uint32" {
-				direccionEnInt = int(Convertir[uint32](tipoActualRegDireccion, direccion))
-			} else {
-				direccionEnInt = int(Convertir[uint8](tipoActualRegDireccion, direccion))
-			}
-
-			tamanio := currentPCB.CPU_reg[instruccionDecodificada[4]]
-			var tamanioEnInt int
-
-			tipoActualRegTamanio := reflect.TypeOf(currentPCB.CPU_reg[instruccionDecodificada[4]]).String()
-
-			if tipoActualRegTamanio == "uint32" {
-				tamanioEnInt = int(Convertir[uint32](tipoActualRegTamanio, tamanio))
-			} else {
-				tamanioEnInt = int(Convertir[uint8](tipoActualRegTamanio, tamanio))
-			}
-
-			puntero := currentPCB.CPU_reg[instruccionDecodificada[5]]
-			var punteroEnInt int
-
-			tipoActualRegPuntero := reflect.TypeOf(currentPCB.CPU_reg[instruccionDecodificada[5]]).String()
-
-			if tipoActualRegPuntero == "uint32" {
-				punteroEnInt = int(Convertir[uint32](tipoActualRegPuntero, puntero))
-			} else {
-				punteroEnInt = int(Convertir[uint8](tipoActualRegPuntero, puntero))
-			}
+			direccionEnInt := int(LeerValor(currentPCB, operandos[0]))
+			tamanioEnInt := int(LeerValor(currentPCB, operandos[1]))
+			punteroEnInt := int(LeerValor(currentPCB, operandos[2]))
 
 			direccionesFisicas := mmu.ObtenerDireccionesFisicas(direccionEnInt, tamanioEnInt, int(currentPCB.PID))
 
 			if cond {
 
-				var fsCreateBody = struct {
-					InterfaceName string
-					FileName      string
-					Address       []globals.DireccionTamanio
-					Size          int
-					Pointer       int
-					Operation     string
-				}{
-					InterfaceName: instruccionDecodificada[1],
-					FileName:      nombre_archivo,
-					Address:       direccionesFisicas,
-					Size:          tamanioEnInt,
-					Pointer:       punteroEnInt,
-					Operation:     "WRITE",
+				currentPCB.Syscall = &pcb.T_Syscall{
+					Name:      instruccionDecodificada[0],
+					Interface: instruccionDecodificada[1],
+					FileName:  nombre_archivo,
+					Addresses: direccionesFisicas,
+					Size:      tamanioEnInt,
+					Pointer:   punteroEnInt,
+					Operation: "WRITE",
 				}
-
-				SendIOData(fsCreateBody, "iodata-dialfs")
-				currentPCB.EvictionReason = "BLOCKED_IO_DIALFS"
+				currentPCB.EvictionReason = pcb.ReasonIODialFS
 			} else {
-				currentPCB.EvictionReason = "EXIT"
+				currentPCB.EvictionReason = pcb.ReasonExit
 			}
 		}
 		pcb.EvictionFlag = true
@@ -283,67 +359,30 @@ func DecodeAndExecute(currentPCB *pcb.T_PCB) {
 		cond, err := HallarInterfaz(instruccionDecodificada[1], "DIALFS")
 		if err != nil {
 			fmt.Print("La interfaz no existe o no acepta operaciones de IO Genéricas")
-			currentPCB.EvictionReason = "EXIT"
+			currentPCB.EvictionReason = pcb.ReasonExit
 		} else {
 			nombre_archivo := instruccionDecodificada[2]
 
-			direccion := currentPCB.CPU_reg[instruccionDecodificada[3]]
-			var direccionEnInt int
-
-			tipoActualRegDireccion := reflect.TypeOf(currentPCB.CPU_reg[instruccionDecodificada[3]]).String()
-
-			if tipoActualRegDireccion == "uint32" {
-				direccionEnInt = int(Convertir[uint32](tipoActualRegDireccion, direccion))
-			} else {
-				direccionEnInt = int(Convertir[uint8](tipoActualRegDireccion, direccion))
-			}
-
-			tamanio := currentPCB.CPU_reg[instruccionDecodificada[4]]
-			var tamanioEnInt int
-
-			tipoActualRegTamanio := reflect.TypeOf(currentPCB.CPU_reg[instruccionDecodificada[4]]).String()
-
-			if tipoActualRegTamanio == "uint32" {
-				tamanioEnInt = int(Convertir[uint32](tipoActualRegTamanio, tamanio))
-			} else {
-				tamanioEnInt = int(Convertir[uint8](tipoActualRegTamanio, tamanio))
-			}
-
-			puntero := currentPCB.CPU_reg[instruccionDecodificada[5]]
-			var punteroEnInt int
-
-			tipoActualRegPuntero := reflect.TypeOf(currentPCB.CPU_reg[instruccionDecodificada[5]]).String()
-
-			if tipoActualRegPuntero == "uint32" {
-				punteroEnInt = int(Convertir[uint32](tipoActualRegPuntero, puntero))
-			} else {
-				punteroEnInt = int(Convertir[uint8](tipoActualRegPuntero, puntero))
-			}
+			direccionEnInt := int(LeerValor(currentPCB, operandos[0]))
+			tamanioEnInt := int(LeerValor(currentPCB, operandos[1]))
+			punteroEnInt := int(LeerValor(currentPCB, operandos[2]))
 
 			direccionesFisicas := mmu.ObtenerDireccionesFisicas(direccionEnInt, tamanioEnInt, int(currentPCB.PID))
 
 			if cond {
 
-				var fsCreateBody = struct {
-					InterfaceName string
-					FileName      string
-					Address       []globals.DireccionTamanio
-					Size          int
-					Pointer       int
-					Operation     string
-				}{
-					InterfaceName: instruccionDecodificada[1],
-					FileName:      nombre_archivo,
-					Address:       direccionesFisicas,
-					Size:          tamanioEnInt,
-					Pointer:       punteroEnInt,
-					Operation:     "READ",
+				currentPCB.Syscall = &pcb.T_Syscall{
+					Name:      instruccionDecodificada[0],
+					Interface: instruccionDecodificada[1],
+					FileName:  nombre_archivo,
+					Addresses: direccionesFisicas,
+					Size:      tamanioEnInt,
+					Pointer:   punteroEnInt,
+					Operation: "READ",
 				}
-
-				SendIOData(fsCreateBody, "iodata-dialfs")
-				currentPCB.EvictionReason = "BLOCKED_IO_DIALFS"
+				currentPCB.EvictionReason = pcb.ReasonIODialFS
 			} else {
-				currentPCB.EvictionReason = "EXIT"
+				currentPCB.EvictionReason = pcb.ReasonExit
 			}
 		}
 		pcb.EvictionFlag = true
@@ -352,7 +391,7 @@ func DecodeAndExecute(currentPCB *pcb.T_PCB) {
 		cond, err := HallarInterfaz(instruccionDecodificada[1], "GENERICA")
 		if err != nil {
 			fmt.Print("La interfaz no existe o no acepta operaciones de IO Genéricas")
-			currentPCB.EvictionReason = "EXIT"
+			currentPCB.EvictionReason = pcb.ReasonExit
 		} else {
 			tiempo_esp, err := strconv.Atoi(instruccionDecodificada[2])
 			if err != nil {
@@ -360,57 +399,48 @@ func DecodeAndExecute(currentPCB *pcb.T_PCB) {
 			}
 			if cond {
 
-				var genSleepBody = struct {
-					InterfaceName string
-					SleepTime     int
-				}{
-					InterfaceName: instruccionDecodificada[1],
-					SleepTime:     tiempo_esp,
+				currentPCB.Syscall = &pcb.T_Syscall{
+					Name:      instruccionDecodificada[0],
+					Interface: instruccionDecodificada[1],
+					SleepTime: tiempo_esp,
 				}
-
-				SendIOData(genSleepBody, "iodata-gensleep")
-				currentPCB.EvictionReason = "BLOCKED_IO_GEN"
+				currentPCB.EvictionReason = pcb.ReasonIOGen
 			} else {
-				currentPCB.EvictionReason = "EXIT"
+				currentPCB.EvictionReason = pcb.ReasonExit
 			}
 		}
 		pcb.EvictionFlag = true
 
+	// SLEEP (Tiempo | Registro): Bloquea al proceso la cantidad de milisegundos indicada, usando un timer de kernel
+	case "SLEEP":
+		currentPCB.SleepTime = ValorOperando(currentPCB, instruccionDecodificada[1])
+		currentPCB.EvictionReason = pcb.ReasonSleep
+		pcb.EvictionFlag = true
+
 	case "IO_STDIN_READ":
 		cond, err := HallarInterfaz(instruccionDecodificada[1], "STDIN")
 	
 		if err != nil {
 			fmt.Print("La interfaz no existe o no acepta operaciones de IO de lectura")
-			currentPCB.EvictionReason = "EXIT"
+			currentPCB.EvictionReason = pcb.ReasonExit
 		} else {
 			if cond {
-				// Obtener la dirección de memoria desde el registro
-				memoryAddress := currentPCB.CPU_reg[instruccionDecodificada[2]]
-				tipoActualReg2 := reflect.TypeOf(currentPCB.CPU_reg[instruccionDecodificada[2]]).String()
-				memoryAddressInt := int(Convertir[uint32](tipoActualReg2, memoryAddress))
-
-				// Obtener la cantidad de datos a leer desde el registro
-				dataSize := currentPCB.CPU_reg[instruccionDecodificada[3]]
-				tipoActualReg3 := reflect.TypeOf(currentPCB.CPU_reg[instruccionDecodificada[3]]).String()
-				dataSizeInt := int(Convertir[uint32](tipoActualReg3, dataSize))
+				// Dirección de memoria y cantidad de datos, desde los registros
+				memoryAddressInt := int(LeerValor(currentPCB, operandos[0]))
+				dataSizeInt := int(LeerValor(currentPCB, operandos[1]))
 
 				direccionesFisicas := mmu.ObtenerDireccionesFisicas(memoryAddressInt, dataSizeInt, int(currentPCB.PID))
 
-				var stdinreadBody = struct {
-					DireccionesFisicas []globals.DireccionTamanio
-					InterfaceName      string
-					Tamanio            int
-				}{
-					DireccionesFisicas: direccionesFisicas,
-					InterfaceName:      instruccionDecodificada[1],
-					Tamanio:            dataSizeInt,
+				currentPCB.Syscall = &pcb.T_Syscall{
+					Name:      instruccionDecodificada[0],
+					Addresses: direccionesFisicas,
+					Interface: instruccionDecodificada[1],
+					Size:      dataSizeInt,
 				}
-
-				SendIOData(stdinreadBody, "iodata-stdin")
-				currentPCB.EvictionReason = "BLOCKED_IO_STDIN"
+				currentPCB.EvictionReason = pcb.ReasonIOStdin
 
 			} else {
-				currentPCB.EvictionReason = "EXIT"
+				currentPCB.EvictionReason = pcb.ReasonExit
 			}
 		}
 		pcb.EvictionFlag = true
@@ -419,195 +449,157 @@ func DecodeAndExecute(currentPCB *pcb.T_PCB) {
 		cond, err := HallarInterfaz(instruccionDecodificada[1], "STDOUT")
 		if err != nil {
 			fmt.Print("La interfaz no existe o no acepta operaciones de IO de escritura")
-			currentPCB.EvictionReason = "EXIT"
+			currentPCB.EvictionReason = pcb.ReasonExit
 		} else {
 			if cond {
-				// Obtener la dirección de memoria desde el registro
-				memoryAddress := currentPCB.CPU_reg[instruccionDecodificada[2]]
-				tipoActualReg2 := reflect.TypeOf(currentPCB.CPU_reg[instruccionDecodificada[2]]).String()
-				memoryAddressInt := int(Convertir[uint32](tipoActualReg2, memoryAddress))
-
-				// Obtener la cantidad de datos a leer desde el registro
-				dataSize := currentPCB.CPU_reg[instruccionDecodificada[3]]
-				tipoActualReg3 := reflect.TypeOf(currentPCB.CPU_reg[instruccionDecodificada[3]]).String()
-				dataSizeInt := int(Convertir[uint32](tipoActualReg3, dataSize))
+				// Dirección de memoria y cantidad de datos, desde los registros
+				memoryAddressInt := int(LeerValor(currentPCB, operandos[0]))
+				dataSizeInt := int(LeerValor(currentPCB, operandos[1]))
 
 				direccionesFisicas := mmu.ObtenerDireccionesFisicas(memoryAddressInt, dataSizeInt, int(currentPCB.PID))
 
-				var stdoutBody = struct {
-					DireccionesFisicas []globals.DireccionTamanio
-					InterfaceName      string
-				}{
-					DireccionesFisicas: direccionesFisicas,
-					InterfaceName:      instruccionDecodificada[1],
+				currentPCB.Syscall = &pcb.T_Syscall{
+					Name:      instruccionDecodificada[0],
+					Addresses: direccionesFisicas,
+					Interface: instruccionDecodificada[1],
 				}
-
-				SendIOData(stdoutBody, "iodata-stdout")
-				currentPCB.EvictionReason = "BLOCKED_IO_STDOUT"
+				currentPCB.EvictionReason = pcb.ReasonIOStdout
 
 			} else {
-				currentPCB.EvictionReason = "EXIT"
+				currentPCB.EvictionReason = pcb.ReasonExit
 			}
 		}
 		pcb.EvictionFlag = true
 
+	// JNZ (Registro, Instrucción): Salta si el registro no es 0.
+	// JNZ (Instrucción): Salta si el último resultado que actualizó FLAGS no fue 0
 	case "JNZ":
-		if currentPCB.CPU_reg[instruccionDecodificada[1]] != 0 {
-			currentPCB.PC = ConvertirUint32(instruccionDecodificada[2])
-			currentPCB.CPU_reg["PC"] = uint32(currentPCB.PC)
+		if len(instruccionDecodificada) > 2 {
+			if ValorOperando(currentPCB, instruccionDecodificada[1]) != 0 {
+				Saltar(currentPCB, instruccionDecodificada[2])
+			}
+		} else if CondicionDeSalto(currentPCB, "JNZ") {
+			Saltar(currentPCB, instruccionDecodificada[1])
 		}
 
-	case "SET":
-		tipoReg := pcb.TipoReg(instruccionDecodificada[1])
-		valor := instruccionDecodificada[2]
+	// JMP, JZ, JG, JL, JGE, JLE (Instrucción): Saltan según FLAGS (JMP siempre). Las comparaciones son sin signo
+	case "JMP", "JZ", "JG", "JL", "JGE", "JLE":
+		if CondicionDeSalto(currentPCB, instruccionDecodificada[0]) {
+			Saltar(currentPCB, instruccionDecodificada[1])
+		}
 
-		if tipoReg == "uint32" {
-			currentPCB.CPU_reg[instruccionDecodificada[1]] = ConvertirUint32(valor)
-		} else {
-			currentPCB.CPU_reg[instruccionDecodificada[1]] = ConvertirUint8(valor)
+	// CALL (Instrucción): Apila la dirección de retorno (el PC de la instrucción siguiente) y salta
+	case "CALL":
+		if motivo := ApilarRegistro(currentPCB, "PC"); motivo != pcb.ReasonNone {
+			currentPCB.EvictionReason = motivo
+			pcb.EvictionFlag = true
+			break
 		}
+		Saltar(currentPCB, instruccionDecodificada[1])
 
-		if instruccionDecodificada[1] == "PC" {
-			currentPCB.PC = ConvertirUint32(valor)
+	// RET: Desapila la dirección de retorno que guardó CALL y vuelve a ella
+	case "RET":
+		if motivo := DesapilarRegistro(currentPCB, "PC"); motivo != pcb.ReasonNone {
+			currentPCB.EvictionReason = motivo
+			pcb.EvictionFlag = true
 		}
 
-	case "SUM":
-		tipoReg1 := pcb.TipoReg(instruccionDecodificada[1])
-		valorReg2 := currentPCB.CPU_reg[instruccionDecodificada[2]]
+	// PUSH (Registro): Apila el valor del registro con su ancho y avanza SP
+	case "PUSH":
+		if motivo := ApilarRegistro(currentPCB, instruccionDecodificada[1]); motivo != pcb.ReasonNone {
+			currentPCB.EvictionReason = motivo
+			pcb.EvictionFlag = true
+		}
 
-		tipoActualReg1 := reflect.TypeOf(currentPCB.CPU_reg[instruccionDecodificada[1]]).String()
-		tipoActualReg2 := reflect.TypeOf(valorReg2).String()
+	// POP (Registro): Retrocede SP y desapila en el registro tantos bytes como su ancho
+	case "POP":
+		if motivo := DesapilarRegistro(currentPCB, instruccionDecodificada[1]); motivo != pcb.ReasonNone {
+			currentPCB.EvictionReason = motivo
+			pcb.EvictionFlag = true
+		}
 
-		if tipoReg1 == "uint32" {
-			currentPCB.CPU_reg[instruccionDecodificada[1]] = Convertir[uint32](tipoActualReg1, currentPCB.CPU_reg[instruccionDecodificada[1]]) + Convertir[uint32](tipoActualReg2, valorReg2)
+	// CMP (Operando 1, Operando 2): Calcula Operando 1 - Operando 2 y actualiza FLAGS sin modificar los operandos
+	case "CMP":
+		DesalojarSiFalla(currentPCB, Operar(currentPCB, "CMP", operandos[0], operandos[1]))
 
-		} else {
-			currentPCB.CPU_reg[instruccionDecodificada[1]] = Convertir[uint8](tipoActualReg1, currentPCB.CPU_reg[instruccionDecodificada[1]]) + Convertir[uint8](tipoActualReg2, valorReg2)
-		}
+	// SET (Destino, Valor): Asigna al registro o a la memoria el valor de un inmediato, un registro o la memoria
+	case "SET":
+		ancho := AnchoEntre(operandos...)
+		EscribirOperando(currentPCB, operandos[0], ancho, LeerOperando(currentPCB, operandos[1], ancho))
 
-		if instruccionDecodificada[1] == "PC" {
-			currentPCB.PC = currentPCB.CPU_reg["PC"].(uint32)
-		}
+	case "SUM":
+		//SUM (Destino, Origen): Suma al Destino el Origen y deja el resultado en el Destino.
+		//El Destino es un registro o memoria; el Origen puede además ser un inmediato.
+		DesalojarSiFalla(currentPCB, Operar(currentPCB, "SUM", operandos[0], operandos[1]))
 
 	case "SUB":
-		//SUB (Registro Destino, Registro Origen): Resta al Registro Destino
-		//el Registro Origen y deja el resultado en el Registro Destino.
-		tipoReg1 := pcb.TipoReg(instruccionDecodificada[1])
-		valorReg2 := currentPCB.CPU_reg[instruccionDecodificada[2]]
+		//SUB (Destino, Origen): Resta al Destino el Origen y deja el resultado en el Destino.
+		DesalojarSiFalla(currentPCB, Operar(currentPCB, "SUB", operandos[0], operandos[1]))
 
-		tipoActualReg1 := reflect.TypeOf(currentPCB.CPU_reg[instruccionDecodificada[1]]).String()
-		tipoActualReg2 := reflect.TypeOf(valorReg2).String()
+	// MUL, DIV, MOD, AND, OR, XOR, SHL, SHR (Destino, Origen): Opera el Destino con el Origen
+	// y deja el resultado en el Destino. Dividir por cero finaliza al proceso.
+	case "MUL", "DIV", "MOD", "AND", "OR", "XOR", "SHL", "SHR":
+		DesalojarSiFalla(currentPCB, Operar(currentPCB, instruccionDecodificada[0], operandos[0], operandos[1]))
 
-		if tipoReg1 == "uint32" {
-			currentPCB.CPU_reg[instruccionDecodificada[1]] = Convertir[uint32](tipoActualReg1, currentPCB.CPU_reg[instruccionDecodificada[1]]) - Convertir[uint32](tipoActualReg2, valorReg2)
+	// NOT (Destino): Invierte los bits del registro o de la memoria
+	case "NOT":
+		Negar(currentPCB, operandos[0])
 
-		} else {
-			currentPCB.CPU_reg[instruccionDecodificada[1]] = Convertir[uint8](tipoActualReg1, currentPCB.CPU_reg[instruccionDecodificada[1]]) - Convertir[uint8](tipoActualReg2, valorReg2)
-		}
+	case "WAIT":
+		currentPCB.RequestedResource = instruccionDecodificada[1]
+		currentPCB.EvictionReason = pcb.ReasonWait
+		pcb.EvictionFlag = true
 
-		if instruccionDecodificada[1] == "PC" {
-			currentPCB.PC = currentPCB.CPU_reg["PC"].(uint32)
+	// WAIT_TIMEOUT (Recurso, Tiempo | Registro, [Registro Estado]): Como WAIT, pero se rinde pasado el tiempo.
+	// Deja 1 en el registro de estado (AX por defecto) si obtuvo la instancia y 0 si venció el tiempo
+	case "WAIT_TIMEOUT":
+		registroEstado := "AX"
+		if len(instruccionDecodificada) > 3 {
+			registroEstado = instruccionDecodificada[3]
 		}
+		if _, existe := currentPCB.CPU_reg[registroEstado]; !existe {
+			Excepcion(currentPCB, pcb.ReasonInvalidRegister, "el registro de estado "+registroEstado+" no existe")
+			break
+		}
+		currentPCB.RequestedResource = instruccionDecodificada[1]
+		currentPCB.WaitTimeout = ValorOperando(currentPCB, instruccionDecodificada[2])
+		currentPCB.StatusRegister = registroEstado
+		currentPCB.EvictionReason = pcb.ReasonWaitTimeout
+		pcb.EvictionFlag = true
 
-	case "WAIT":
+	// TRY_WAIT (Recurso, Registro Estado): Intenta tomar una instancia sin bloquearse. Deja 1 en el registro si la obtuvo y 0 si no
+	case "TRY_WAIT":
+		if _, existe := currentPCB.CPU_reg[instruccionDecodificada[2]]; !existe {
+			Excepcion(currentPCB, pcb.ReasonInvalidRegister, "el registro de estado "+instruccionDecodificada[2]+" no existe")
+			break
+		}
 		currentPCB.RequestedResource = instruccionDecodificada[1]
-		fmt.Print("Requested Resource: ", currentPCB.RequestedResource+"\n") // *Lo hace bien
-		currentPCB.EvictionReason = "WAIT"
+		currentPCB.StatusRegister = instruccionDecodificada[2]
+		currentPCB.EvictionReason = pcb.ReasonTryWait
 		pcb.EvictionFlag = true
 
 	case "SIGNAL":
 		currentPCB.RequestedResource = instruccionDecodificada[1]
-		currentPCB.EvictionReason = "SIGNAL"
+		currentPCB.EvictionReason = pcb.ReasonSignal
 		pcb.EvictionFlag = true
 
 	case "MOV_OUT":
-		//MOV_OUT(Registro Dirección, Registro Datos): Lee el valor del Registro Datos y lo escribe en la dirección física de memoria
-		//obtenida a partir de la Dirección Lógica almacenada en el Registro Dirección.
-
-		// Leer el valor y tamaño del registro de datos (2)
-		var tamanio2 int
-		tipoReg2 := pcb.TipoReg(instruccionDecodificada[2])
-		if tipoReg2 == "uint32" {
-			tamanio2 = 4
-		} else if tipoReg2 == "uint8" {
-			tamanio2 = 1
-		}
-
-		// Leer la dirección lógica del registro de dirección (1)
-		valorReg1 := currentPCB.CPU_reg[instruccionDecodificada[1]]
-		tipoActualReg1 := reflect.TypeOf(valorReg1).String()
-
-		direc_log := Convertir[uint32](tipoActualReg1, valorReg1)
-
-		direcsFisicas := mmu.ObtenerDireccionesFisicas(int(direc_log), tamanio2, int(currentPCB.PID))
-
-		valorReg2 := currentPCB.CPU_reg[instruccionDecodificada[2]]
-		tipoActualReg2 := reflect.TypeOf(valorReg2).String()
-
-		var valorEnBytes []byte
-
-		if tipoReg2 == "uint32" {
-			valor2EnUint := Convertir[uint32](tipoActualReg2, valorReg2)
-			valorEnBytes = []byte{byte(valor2EnUint)} 
-
-		} else {
-			valor2EnUint := Convertir[uint8](tipoActualReg2, valorReg2) 
-			valorEnBytes = []byte{valor2EnUint}
-		}
-		valorEnBytesRelleno := make([]byte, tamanio2)
-		inicio := len(valorEnBytesRelleno) - len(valorEnBytes)
-		// Realizar la copia en la posición calculada
-		copy(valorEnBytesRelleno[inicio:], valorEnBytes)
-
-		solicitudesmemoria.SolicitarEscritura(direcsFisicas, valorEnBytesRelleno, int(currentPCB.PID)) //([direccion fisica y tamanio], valorAEscribir, pid
+		//MOV_OUT(Dirección, Datos): Escribe el valor de Datos (registro o inmediato) en la dirección física de memoria
+		//obtenida a partir de la Dirección Lógica. La Dirección puede ser un registro que la contiene, [EBX+4] o un número.
+		direccion := operandos[0].ComoDireccion()
+		ancho := AnchoEntre(operandos[1])
+		EscribirMemoria(currentPCB, direccion.Direccion(currentPCB), ancho, LeerOperando(currentPCB, operandos[1], ancho))
 
 		//----------------------------------------------------------------------------
 
-		// MOV_IN (Registro Datos, Registro Dirección): Lee el valor
-		// de memoria correspondiente a la Dirección Lógica que se encuentra
-		// en el Registro Dirección y lo almacena en el Registro Datos.
+		// MOV_IN (Registro Datos, Dirección): Lee el valor
+		// de memoria correspondiente a la Dirección Lógica (un registro que
+		// la contiene, [EBX+4] o un número) y lo almacena en el Registro Datos.
 
 	case "MOV_IN":
-
-		var tamanio int
-		tipoReg1 := pcb.TipoReg(instruccionDecodificada[1])
-		if tipoReg1 == "uint32" {
-			tamanio = 4
-		} else if tipoReg1 == "uint8" {
-			tamanio = 1
-		}
-
-		valorReg2 := currentPCB.CPU_reg[instruccionDecodificada[2]]
-		tipoActualReg2 := reflect.TypeOf(valorReg2).String()
-
-		direc_log := Convertir[uint32](tipoActualReg2, valorReg2)
-
-		fmt.Println("El valor de la direc logica es", int(direc_log))
-
-		// Obtenemos la direcion fisica del reg direccion
-		direcsFisicas := mmu.ObtenerDireccionesFisicas(int(direc_log), tamanio, int(currentPCB.PID))
-
-		fmt.Println("Direcciones fisicas: ", direcsFisicas)
-
-		//Obtenemos el valor guardado en las direcciones fisicas
-		datos := solicitudesmemoria.SolicitarLectura(direcsFisicas, int(currentPCB.PID))
-		fmt.Println("Los datos obtenidos de memoria son: ", datos)
-		
-		// Almacenamos lo leido en el registro destino
-		var datosAAlmacenar uint64
-
-		if tipoReg1 == "uint32" {
-
-			bigInt := big.NewInt(0).SetBytes(datos)
-			datosAAlmacenar = bigInt.Uint64()
-
-			currentPCB.CPU_reg[instruccionDecodificada[1]] = uint32(datosAAlmacenar)
-
-		} else {
-			
-			datosAAlmacenar = uint64(datos[0])
-			currentPCB.CPU_reg[instruccionDecodificada[1]] = uint8(datosAAlmacenar)
-		}
+		ancho := operandos[0].Ancho()
+		direccion := operandos[1].ComoDireccion()
+		EscribirRegistro(currentPCB, operandos[0].Registro, LeerOperando(currentPCB, direccion, ancho))
 
 		//-----------------------------------------------------------------------------
 		//COPY_STRING (Tamaño): Toma del string apuntado por el registro SI y
@@ -615,31 +607,17 @@ func DecodeAndExecute(currentPCB *pcb.T_PCB) {
 		//posición de memoria apuntada por el registro DI.
 
 	case "COPY_STRING":
-		tamanio := globals.PasarAInt(instruccionDecodificada[1])
+		tamanio := int(LeerValor(currentPCB, operandos[0]))
 		//Buscar la direccion logica del registro SI
-		valorRegSI := currentPCB.CPU_reg["SI"]
-		tipoActualRegSI := reflect.TypeOf(valorRegSI).String()
-		var direc_logicaSI int
-		
-		if tipoActualRegSI == "uint32" {
-			valorSIConv := Convertir[uint32](tipoActualRegSI, valorRegSI)
-			direc_logicaSI = int(valorSIConv)
-
-		} else {
-			valorSIConv := Convertir[uint8](tipoActualRegSI, valorRegSI)
-			direc_logicaSI = int(valorSIConv)
-		}
+		direc_logicaSI := int(LeerRegistro(currentPCB, "SI"))
 
 		direcsFisicasSI := mmu.ObtenerDireccionesFisicas(direc_logicaSI, tamanio, int(currentPCB.PID))
 
 		// Lee lo que hay en esa direccion fisica pero no todo, lees lo que te pasaron x param
 		datos := solicitudesmemoria.SolicitarLectura(direcsFisicasSI, int(currentPCB.PID))
-		fmt.Println("Los datos leidos de memoria son: ", datos)
 
 		// Busca la direccion logica del registro DI
-		valorRegDI := currentPCB.CPU_reg["DI"]
-		tipoActualRegDI := reflect.TypeOf(valorRegDI).String()
-		direc_logicaDI := int(Convertir[uint32](tipoActualRegDI, valorRegDI))
+		direc_logicaDI := int(LeerRegistro(currentPCB, "DI"))
 
 		// Obtiene la direccion Fisica asociada
 		direcsFisicasDI := mmu.ObtenerDireccionesFisicas(direc_logicaDI, tamanio, int(currentPCB.PID))
@@ -653,11 +631,23 @@ func DecodeAndExecute(currentPCB *pcb.T_PCB) {
 	//RESIZE (Tamaño)
 	case "RESIZE":
 		tamanio := globals.PasarAInt(instruccionDecodificada[1])
+		if currentPCB.StackLimit > 0 {
+			// La pila está al final del proceso: si los datos crecen hasta ella, se la mueve más arriba
+			if tamanio > int(currentPCB.StackBase) {
+				if motivo := MoverPila(currentPCB, tamanio); motivo != pcb.ReasonNone {
+					currentPCB.EvictionReason = motivo
+					pcb.EvictionFlag = true
+				}
+				break
+			}
+			// y no se puede achicar por debajo de ella
+			log.Printf("PID: %d - RESIZE %d recortaría la pila, se redimensiona a %d", currentPCB.PID, tamanio, currentPCB.StackLimit)
+			tamanio = int(currentPCB.StackLimit)
+		}
 		
 		respuestaResize := solicitudesmemoria.Resize(tamanio)
-		fmt.Println("el resize devuelve", respuestaResize)
 		if respuestaResize != "\"OK\"" {
-			currentPCB.EvictionReason = "OUT_OF_MEMORY"
+			currentPCB.EvictionReason = pcb.ReasonOutOfMemory
 			pcb.EvictionFlag = true
 		}
 	}
@@ -665,31 +655,6 @@ func DecodeAndExecute(currentPCB *pcb.T_PCB) {
 
 type Uint interface{ ~uint8 | ~uint32 }
 
-func Convertir[T Uint](tipo string, parametro interface{}) T {
-
-	if parametro == "" {
-		fmt.Printf("La cadena de texto está vacía")
-	}
-
-	fmt.Print("El tipo a convertir es: ", tipo)
-
-	switch tipo {
-	case "uint8":
-		valor := parametro.(uint8)
-		return T(valor)
-	case "uint32":
-		valor := parametro.(uint32)
-		return T(valor)
-	case "float64":
-		valor := parametro.(float64)
-		return T(valor)
-	case "int":
-		valor := parametro.(int)
-		return T(valor)
-	}
-	return T(0)
-}
-
 type SearchInterface struct {
 	Name string `json:"name"`
 	Type string `json:"type"`
@@ -701,8 +666,6 @@ func HallarInterfaz(nombre string, tipo string) (bool, error) {
 		Type: tipo,
 	}
 
-	fmt.Println("Interfaz a buscar: ", interf)
-
 	jsonData, err := json.Marshal(interf)
 	if err != nil {
 		return false, fmt.Errorf("failed to encode interface: %v", err)
@@ -724,43 +687,9 @@ func HallarInterfaz(nombre string, tipo string) (bool, error) {
 		return false, fmt.Errorf("failed to decode response: %v", err)
 	}
 
-	fmt.Println("Interfaz encontrada")
-
 	return response, nil
 }
 
-/*
-	 SendIOData: Comunica la información necesaria a kernel para el uso de cualquier body de interfaz de entrada/salida
-
-	 @param datum: Estructura con la información necesaria para la comunicación (La estructura usada va a depender de la interfaz a utilizar)
-	 @param endpoint: Endpoint al que se va a enviar la información
-		- "iodata-gensleep"
-		- "iodata-stdin"
-		- "iodata-stdout"
-		- "iodata-dialfs"
-	 @return error: Error en caso de que la comunicación falle
-
-*
-*/
-func SendIOData(datum interface{}, endpoint string) error {
-	jsonData, err := json.Marshal(datum)
-	if err != nil {
-		return fmt.Errorf("failed to encode interface: %v", err)
-	}
-
-	url := fmt.Sprintf("http://%s:%d/%s", globals.Configcpu.IP_kernel, globals.Configcpu.Port_kernel, endpoint)
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("POST request failed. Failed to send interface: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected response status: %s", resp.Status)
-	}
-
-	return nil
-}
-
 func ConvertirUint8(parametro string) uint8 {
 	parametroConvertido, err := strconv.Atoi(parametro)
 	if err != nil {
@@ -769,6 +698,22 @@ func ConvertirUint8(parametro string) uint8 {
 	return uint8(parametroConvertido)
 }
 
+/**
+ * ValorOperando: Devuelve el valor de un operando que puede ser un registro, un número, un inmediato o memoria
+
+ * @param currentPCB: proceso en ejecución
+ * @param operando: operando tal como aparece en la instrucción
+ * @return uint32: valor del operando
+*/
+func ValorOperando(currentPCB *pcb.T_PCB, operando string) uint32 {
+	operandoDecodificado, err := ParsearOperando(operando)
+	if err != nil {
+		fmt.Print("Error al convertir el operando: ", err)
+		return 0
+	}
+	return LeerValor(currentPCB, operandoDecodificado)
+}
+
 func ConvertirUint32(parametro string) uint32 {
 	parametroConvertido, err := strconv.Atoi(parametro)
 	if err != nil {
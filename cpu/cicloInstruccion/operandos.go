@@ -0,0 +1,305 @@
+package cicloInstruccion
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	mmu "github.com/sisoputnfrba/tp-golang/cpu/mmu"
+	solicitudesmemoria "github.com/sisoputnfrba/tp-golang/cpu/solicitudesMemoria"
+	"github.com/sisoputnfrba/tp-golang/utils/assembler"
+	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+)
+
+type TipoOperando int
+
+const (
+	OperandoRegistro 	= TipoOperando(assembler.OperandRegister) 	// AX, EBX, ...
+	OperandoInmediato 	= TipoOperando(assembler.OperandImmediate) 	// #5, #0x10 o 5
+	OperandoMemoria 	= TipoOperando(assembler.OperandMemory) 	// [EBX], [EBX+4], [EBX-4] o [100]
+)
+
+// Operando ya decodificado. Se arma una vez por instrucción y después se lee o escribe sin volver a interpretar el texto.
+type T_Operando struct {
+	Tipo 			TipoOperando
+	Registro 		string 	// registro, o base de la dirección en un operando de memoria ("" si es una dirección absoluta)
+	Valor 			uint32 	// valor inmediato, o desplazamiento / dirección absoluta en un operando de memoria
+}
+
+/**
+ * ParsearOperando: Interpreta el texto de un operando, con la misma sintaxis que valida memoria al cargar el script
+
+ * @param texto: operando tal como aparece en la instrucción
+ * @return T_Operando: operando decodificado
+ * @return error: si no es un registro, un inmediato ni una dirección de memoria válida
+**/
+func ParsearOperando(texto string) (T_Operando, error) {
+	operando, err := assembler.ParseOperand(texto)
+	if err != nil {
+		return T_Operando{}, err
+	}
+	return T_Operando{Tipo: TipoOperando(operando.Kind), Registro: operando.Register, Valor: operando.Value}, nil
+}
+
+/**
+ * ParsearOperandos: Interpreta todos los operandos de una instrucción
+
+ * @param textos: operandos tal como aparecen en la instrucción
+ * @return []T_Operando: operandos decodificados
+ * @return error: el primer operando inválido
+**/
+func ParsearOperandos(textos []string) ([]T_Operando, error) {
+	operandos := make([]T_Operando, 0, len(textos))
+	for _, texto := range textos {
+		operando, err := ParsearOperando(texto)
+		if err != nil {
+			return nil, err
+		}
+		operandos = append(operandos, operando)
+	}
+	return operandos, nil
+}
+
+// Instrucciones cuyos operandos se decodifican con ParsearOperando, y cuántos llevan
+var InstruccionesConOperandos = map[string]int{
+	"SET": 		2,
+	"SUM": 		2,
+	"SUB": 		2,
+	"MUL": 		2,
+	"DIV": 		2,
+	"MOD": 		2,
+	"AND": 		2,
+	"OR": 		2,
+	"XOR": 		2,
+	"SHL": 		2,
+	"SHR": 		2,
+	"CMP": 		2,
+	"NOT": 		1,
+	"MOV_IN": 	2,
+	"MOV_OUT": 	2,
+	"COPY_STRING": 	1,
+	"IO_STDIN_READ": 	2,
+	"IO_STDOUT_WRITE": 	2,
+	"IO_FS_TRUNCATE": 	1,
+	"IO_FS_WRITE": 		3,
+	"IO_FS_READ": 		3,
+}
+
+// Argumentos que van antes de los operandos y no se decodifican: la interfaz y el nombre de archivo de las operaciones de IO
+var ArgumentosPrevios = map[string]int{
+	"IO_STDIN_READ": 	1,
+	"IO_STDOUT_WRITE": 	1,
+	"IO_FS_TRUNCATE": 	2,
+	"IO_FS_WRITE": 		2,
+	"IO_FS_READ": 		2,
+}
+
+// OperandosDe: Textos de los operandos de una instrucción ya separada, sin los argumentos previos
+func OperandosDe(instruccionDecodificada []string) []string {
+	desde := 1 + ArgumentosPrevios[instruccionDecodificada[0]]
+	if desde > len(instruccionDecodificada) {
+		return nil
+	}
+	return instruccionDecodificada[desde:]
+}
+
+/**
+ * DecodificarOperandos: Decodifica los operandos de una instrucción de InstruccionesConOperandos y valida su forma
+
+ * @param instruccion: nombre de la instrucción
+ * @param textos: operandos tal como aparecen en la instrucción
+ * @return []T_Operando: operandos decodificados
+ * @return error: si falta o sobra un operando, alguno es inválido o el destino es un inmediato
+**/
+func DecodificarOperandos(instruccion string, textos []string) ([]T_Operando, error) {
+	if cantidad := InstruccionesConOperandos[instruccion]; len(textos) != cantidad {
+		return nil, fmt.Errorf("%s lleva %d operandos y se recibieron %d", instruccion, cantidad, len(textos))
+	}
+
+	operandos, err := ParsearOperandos(textos)
+	if err != nil {
+		return nil, err
+	}
+
+	switch instruccion {
+	case "CMP", "MOV_OUT", "COPY_STRING", "IO_STDIN_READ", "IO_STDOUT_WRITE", "IO_FS_TRUNCATE", "IO_FS_WRITE", "IO_FS_READ":
+		// Ningún operando se escribe (CMP, COPY_STRING y las de IO solo leen valores) o el primero es una dirección (MOV_OUT)
+	case "MOV_IN":
+		if operandos[0].Tipo != OperandoRegistro {
+			return nil, fmt.Errorf("MOV_IN: el destino debe ser un registro")
+		}
+	default:
+		if operandos[0].Tipo == OperandoInmediato {
+			return nil, fmt.Errorf("%s: un inmediato no puede ser destino", instruccion)
+		}
+	}
+	return operandos, nil
+}
+
+// Ancho: Tamaño en bytes de un operando registro. Los inmediatos y la memoria no tienen ancho propio (0).
+func (o T_Operando) Ancho() int {
+	if o.Tipo != OperandoRegistro {
+		return 0
+	}
+	if pcb.TipoReg(o.Registro) == "uint32" {
+		return 4
+	}
+	return 1
+}
+
+// ComoDireccion: Operando de dirección de MOV_IN / MOV_OUT. Un registro o un inmediato se toman como la dirección misma.
+func (o T_Operando) ComoDireccion() T_Operando {
+	switch o.Tipo {
+	case OperandoRegistro:
+		return T_Operando{Tipo: OperandoMemoria, Registro: o.Registro}
+	case OperandoInmediato:
+		return T_Operando{Tipo: OperandoMemoria, Valor: o.Valor}
+	}
+	return o
+}
+
+// Direccion: Dirección lógica de un operando de memoria
+func (o T_Operando) Direccion(currentPCB *pcb.T_PCB) uint32 {
+	if o.Registro == "" {
+		return o.Valor
+	}
+	return LeerRegistro(currentPCB, o.Registro) + o.Valor
+}
+
+// AnchoEntre: Ancho de una operación entre dos operandos: el del primer registro que aparezca, o 4 bytes si no hay registros
+func AnchoEntre(operandos ...T_Operando) int {
+	for _, operando := range operandos {
+		if ancho := operando.Ancho(); ancho > 0 {
+			return ancho
+		}
+	}
+	return 4
+}
+
+/**
+ * LeerOperando: Valor de un operando en el ancho indicado. Los operandos de memoria se leen a través de la MMU.
+
+ * @param currentPCB: proceso en ejecución
+ * @param operando: operando a leer
+ * @param ancho: 1 o 4 bytes
+ * @return uint32: valor (truncado al ancho)
+**/
+func LeerOperando(currentPCB *pcb.T_PCB, operando T_Operando, ancho int) uint32 {
+	var valor uint32
+	switch operando.Tipo {
+	case OperandoRegistro:
+		valor = LeerRegistro(currentPCB, operando.Registro)
+	case OperandoInmediato:
+		valor = operando.Valor
+	case OperandoMemoria:
+		valor = LeerMemoria(currentPCB, operando.Direccion(currentPCB), ancho)
+	}
+	if ancho == 1 {
+		return uint32(uint8(valor))
+	}
+	return valor
+}
+
+// LeerValor: Valor de un operando en su propio ancho (el del registro, o 4 bytes)
+func LeerValor(currentPCB *pcb.T_PCB, operando T_Operando) uint32 {
+	return LeerOperando(currentPCB, operando, AnchoEntre(operando))
+}
+
+/**
+ * EscribirOperando: Guarda un valor en un registro o, a través de la MMU, en memoria
+
+ * @param currentPCB: proceso en ejecución
+ * @param operando: operando destino (no puede ser un inmediato)
+ * @param ancho: 1 o 4 bytes
+ * @param valor: valor a guardar
+ * @return error: si el destino es un inmediato
+**/
+func EscribirOperando(currentPCB *pcb.T_PCB, operando T_Operando, ancho int, valor uint32) error {
+	switch operando.Tipo {
+	case OperandoRegistro:
+		EscribirRegistro(currentPCB, operando.Registro, valor)
+	case OperandoMemoria:
+		EscribirMemoria(currentPCB, operando.Direccion(currentPCB), ancho, valor)
+	default:
+		return fmt.Errorf("un inmediato no puede ser destino")
+	}
+	return nil
+}
+
+/**
+ * LeerRegistro: Valor de un registro. Después de NormalizarRegistros ya tiene su tipo, así que no hace falta reflect.
+
+ * @param currentPCB: proceso en ejecución
+ * @param registro: nombre del registro
+ * @return uint32: valor del registro
+**/
+func LeerRegistro(currentPCB *pcb.T_PCB, registro string) uint32 {
+	switch valor := currentPCB.CPU_reg[registro].(type) {
+	case uint8:
+		return uint32(valor)
+	case uint32:
+		return valor
+	case float64:
+		return uint32(valor)
+	case int:
+		return uint32(valor)
+	}
+	return 0
+}
+
+/**
+ * EscribirRegistro: Guarda un valor en un registro con el tipo que le corresponde. Si es PC también mueve el PC del proceso.
+
+ * @param currentPCB: proceso en ejecución
+ * @param registro: nombre del registro
+ * @param valor: valor a guardar (se trunca al ancho del registro)
+**/
+func EscribirRegistro(currentPCB *pcb.T_PCB, registro string, valor uint32) {
+	if pcb.TipoReg(registro) == "uint32" {
+		currentPCB.CPU_reg[registro] = valor
+	} else {
+		currentPCB.CPU_reg[registro] = uint8(valor)
+	}
+
+	if registro == "PC" {
+		currentPCB.PC = valor
+	}
+}
+
+// NormalizarRegistros: Al recibir el PCB los registros llegan como float64 (JSON); se pasan una sola vez a su tipo
+func NormalizarRegistros(currentPCB *pcb.T_PCB) {
+	for _, registro := range pcb.Registers {
+		if _, ok := currentPCB.CPU_reg[registro]; ok {
+			EscribirRegistro(currentPCB, registro, LeerRegistro(currentPCB, registro))
+		}
+	}
+	currentPCB.CPU_reg["PC"] = currentPCB.PC
+}
+
+// Registros: Valor de cada registro del proceso, para la traza y el depurador
+func Registros(currentPCB *pcb.T_PCB) map[string]uint32 {
+	registros := make(map[string]uint32, len(pcb.Registers))
+	for _, registro := range pcb.Registers {
+		registros[registro] = LeerRegistro(currentPCB, registro)
+	}
+	return registros
+}
+
+// LeerMemoria: Lee un valor big-endian de 1 o 4 bytes de la dirección lógica indicada, a través de la MMU
+func LeerMemoria(currentPCB *pcb.T_PCB, direccion uint32, ancho int) uint32 {
+	direcsFisicas := mmu.ObtenerDireccionesFisicas(int(direccion), ancho, int(currentPCB.PID))
+	datos := solicitudesmemoria.SolicitarLectura(direcsFisicas, int(currentPCB.PID))
+
+	var valor uint32
+	for _, dato := range datos {
+		valor = valor<<8 | uint32(dato)
+	}
+	return valor
+}
+
+// EscribirMemoria: Escribe un valor big-endian de 1 o 4 bytes en la dirección lógica indicada, a través de la MMU
+func EscribirMemoria(currentPCB *pcb.T_PCB, direccion uint32, ancho int, valor uint32) {
+	bytesValor := binary.BigEndian.AppendUint32(nil, valor)[4-ancho:]
+
+	direcsFisicas := mmu.ObtenerDireccionesFisicas(int(direccion), ancho, int(currentPCB.PID))
+	solicitudesmemoria.SolicitarEscritura(direcsFisicas, bytesValor, int(currentPCB.PID))
+}
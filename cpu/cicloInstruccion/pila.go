@@ -0,0 +1,168 @@
+package cicloInstruccion
+
+import (
+	"encoding/binary"
+	"log"
+
+	"github.com/sisoputnfrba/tp-golang/cpu/globals"
+	mmu "github.com/sisoputnfrba/tp-golang/cpu/mmu"
+	solicitudesmemoria "github.com/sisoputnfrba/tp-golang/cpu/solicitudesMemoria"
+	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+)
+
+// Tamaño de la pila si la configuración no lo indica
+const tamanioPilaPorDefecto = 64
+
+/**
+ * ReservarPila: Reserva la pila del proceso la primera vez que la usa. Se ubica a continuación de sus páginas actuales,
+ * agrandando el proceso con RESIZE, y crece hacia direcciones mayores: SP apunta al primer byte libre.
+ * Si después un RESIZE agranda los datos hasta la pila, se la mueve más arriba (ver MoverPila).
+
+ * @param currentPCB: proceso en ejecución
+ * @return pcb.EvictionReason: ReasonOutOfMemory si memoria no pudo agrandar al proceso, si no ReasonNone
+**/
+func ReservarPila(currentPCB *pcb.T_PCB) pcb.EvictionReason {
+	if currentPCB.StackLimit > 0 {
+		return pcb.ReasonNone
+	}
+
+	tamanio := globals.Configcpu.Stack_size
+	if tamanio <= 0 {
+		tamanio = tamanioPilaPorDefecto
+	}
+	base := mmu.PedirTamTablaPaginas(int(currentPCB.PID)) * mmu.SolicitarTamPagina()
+
+	if solicitudesmemoria.Resize(base+tamanio) != "\"OK\"" {
+		return pcb.ReasonOutOfMemory
+	}
+
+	currentPCB.StackBase = uint32(base)
+	currentPCB.StackLimit = uint32(base + tamanio)
+	currentPCB.CPU_reg["SP"] = uint32(base)
+	log.Printf("PID: %d - Pila reservada - Base: %d - Límite: %d", currentPCB.PID, base, base+tamanio)
+	return pcb.ReasonNone
+}
+
+/**
+ * MoverPila: Reubica la pila a continuación de los datos cuando un RESIZE los agranda hasta pisarla.
+ * Se copia lo apilado y SP conserva su distancia a la base.
+
+ * @param currentPCB: proceso en ejecución
+ * @param tamanioDatos: tamaño pedido por el RESIZE para los datos del proceso
+ * @return pcb.EvictionReason: ReasonOutOfMemory si memoria no pudo agrandar al proceso, si no ReasonNone
+**/
+func MoverPila(currentPCB *pcb.T_PCB, tamanioDatos int) pcb.EvictionReason {
+	tamPagina := mmu.SolicitarTamPagina()
+	base := (tamanioDatos + tamPagina - 1) / tamPagina * tamPagina
+	tamanio := int(currentPCB.StackLimit - currentPCB.StackBase)
+	usados := int(LeerRegistro(currentPCB, "SP") - currentPCB.StackBase)
+
+	// Se lee antes de redimensionar: la pila vieja y la nueva pueden superponerse
+	var apilado []byte
+	if usados > 0 {
+		direcsFisicas := mmu.ObtenerDireccionesFisicas(int(currentPCB.StackBase), usados, int(currentPCB.PID))
+		apilado = solicitudesmemoria.SolicitarLectura(direcsFisicas, int(currentPCB.PID))
+	}
+
+	if solicitudesmemoria.Resize(base+tamanio) != "\"OK\"" {
+		return pcb.ReasonOutOfMemory
+	}
+
+	if len(apilado) > 0 {
+		direcsFisicas := mmu.ObtenerDireccionesFisicas(base, len(apilado), int(currentPCB.PID))
+		solicitudesmemoria.SolicitarEscritura(direcsFisicas, apilado, int(currentPCB.PID))
+	}
+
+	log.Printf("PID: %d - Pila movida - Base: %d -> %d - Límite: %d", currentPCB.PID, currentPCB.StackBase, base, base+tamanio)
+	currentPCB.StackBase = uint32(base)
+	currentPCB.StackLimit = uint32(base + tamanio)
+	currentPCB.CPU_reg["SP"] = uint32(base + usados)
+	return pcb.ReasonNone
+}
+
+/**
+ * Apilar: Escribe los bytes en el tope de la pila a través de la MMU y avanza SP
+
+ * @param currentPCB: proceso en ejecución
+ * @param valor: bytes a apilar
+ * @return pcb.EvictionReason: ReasonStackOverflow si no entran, ReasonOutOfMemory si no se pudo reservar la pila, si no ReasonNone
+**/
+func Apilar(currentPCB *pcb.T_PCB, valor []byte) pcb.EvictionReason {
+	if motivo := ReservarPila(currentPCB); motivo != pcb.ReasonNone {
+		return motivo
+	}
+
+	sp := LeerRegistro(currentPCB, "SP")
+	if sp < currentPCB.StackBase || uint64(sp)+uint64(len(valor)) > uint64(currentPCB.StackLimit) {
+		return pcb.ReasonStackOverflow
+	}
+
+	direcsFisicas := mmu.ObtenerDireccionesFisicas(int(sp), len(valor), int(currentPCB.PID))
+	solicitudesmemoria.SolicitarEscritura(direcsFisicas, valor, int(currentPCB.PID))
+
+	currentPCB.CPU_reg["SP"] = sp + uint32(len(valor))
+	return pcb.ReasonNone
+}
+
+/**
+ * Desapilar: Retrocede SP y lee del tope de la pila a través de la MMU
+
+ * @param currentPCB: proceso en ejecución
+ * @param tamanio: cantidad de bytes a desapilar
+ * @return []byte: bytes leídos
+ * @return pcb.EvictionReason: ReasonStackUnderflow si la pila no tiene tantos bytes, si no ReasonNone
+**/
+func Desapilar(currentPCB *pcb.T_PCB, tamanio int) ([]byte, pcb.EvictionReason) {
+	sp := LeerRegistro(currentPCB, "SP")
+	if currentPCB.StackLimit == 0 || sp > currentPCB.StackLimit || sp < currentPCB.StackBase+uint32(tamanio) {
+		return nil, pcb.ReasonStackUnderflow
+	}
+
+	sp -= uint32(tamanio)
+	direcsFisicas := mmu.ObtenerDireccionesFisicas(int(sp), tamanio, int(currentPCB.PID))
+	datos := solicitudesmemoria.SolicitarLectura(direcsFisicas, int(currentPCB.PID))
+	if len(datos) < tamanio {
+		return nil, pcb.ReasonStackUnderflow
+	}
+
+	currentPCB.CPU_reg["SP"] = sp
+	return datos[len(datos)-tamanio:], pcb.ReasonNone
+}
+
+/**
+ * ApilarRegistro: Apila el valor de un registro con su ancho (1 byte para AX..DX, 4 para EAX..EDX, SI, DI, PC, SP)
+
+ * @param currentPCB: proceso en ejecución
+ * @param registro: registro a apilar
+ * @return pcb.EvictionReason: motivo de desalojo si falló, si no ReasonNone
+**/
+func ApilarRegistro(currentPCB *pcb.T_PCB, registro string) pcb.EvictionReason {
+	valor := LeerRegistro(currentPCB, registro)
+
+	if pcb.TipoReg(registro) == "uint32" {
+		return Apilar(currentPCB, binary.BigEndian.AppendUint32(nil, valor))
+	}
+	return Apilar(currentPCB, []byte{uint8(valor)})
+}
+
+/**
+ * DesapilarRegistro: Desapila en el registro tantos bytes como su ancho
+
+ * @param currentPCB: proceso en ejecución
+ * @param registro: registro destino
+ * @return pcb.EvictionReason: motivo de desalojo si falló, si no ReasonNone
+**/
+func DesapilarRegistro(currentPCB *pcb.T_PCB, registro string) pcb.EvictionReason {
+	ancho := T_Operando{Tipo: OperandoRegistro, Registro: registro}.Ancho()
+	datos, motivo := Desapilar(currentPCB, ancho)
+	if motivo != pcb.ReasonNone {
+		return motivo
+	}
+
+	var valor uint32
+	for _, dato := range datos {
+		valor = valor<<8 | uint32(dato)
+	}
+	EscribirRegistro(currentPCB, registro, valor)
+	return pcb.ReasonNone
+}
@@ -0,0 +1,162 @@
+/**
+ * trace-replay: Reproduce trazas de CPU (trace_dir) sobre un banco de registros nuevo para verificar que la ejecución es determinista.
+ *
+ * Uso: go run ./cpu/cmd/trace-replay traza.jsonl...
+ *
+ * Las instrucciones que solo usan registros (aritméticas, CMP y saltos) se vuelven a ejecutar con el mismo código de CPU y
+ * se comparan con los registros de la traza. Las que dependen de memoria, kernel o IO no se pueden repetir sin los
+ * demás módulos: de ellas se aplican los cambios registrados. Sale con 1 si alguna reejecución dio distinto.
+*/
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/sisoputnfrba/tp-golang/cpu/cicloInstruccion"
+	"github.com/sisoputnfrba/tp-golang/cpu/traza"
+	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+)
+
+// Instrucciones que, sin operandos de memoria, dependen solo de los registros
+var reproducibles = map[string]bool{
+	"SET": true, "SUM": true, "SUB": true, "MUL": true, "DIV": true, "MOD": true,
+	"AND": true, "OR": true, "XOR": true, "SHL": true, "SHR": true, "NOT": true, "CMP": true,
+	"JMP": true, "JZ": true, "JNZ": true, "JG": true, "JL": true, "JGE": true, "JLE": true,
+}
+
+type resultado struct {
+	pasos 			int
+	reejecutados 	int
+	externos 		int // registros que cambiaron entre instrucciones (kernel, depurador)
+	divergencias 	int
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "uso: trace-replay traza.jsonl...")
+		os.Exit(2)
+	}
+
+	// Ejecutar loguea cada instrucción como lo hace CPU; acá no interesa
+	log.SetOutput(io.Discard)
+
+	fallo := false
+	for _, ruta := range os.Args[1:] {
+		res, err := reproducir(ruta)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			fallo = true
+			continue
+		}
+		fmt.Printf("%s: %d instrucciones, %d reejecutadas, %d cambios externos, %d divergencias\n",
+			ruta, res.pasos, res.reejecutados, res.externos, res.divergencias)
+		fallo = fallo || res.divergencias > 0
+	}
+
+	if fallo {
+		os.Exit(1)
+	}
+}
+
+// reproducir: Recorre la traza de un archivo con un banco de registros en cero por proceso
+func reproducir(ruta string) (resultado, error) {
+	var res resultado
+
+	archivo, err := os.Open(ruta)
+	if err != nil {
+		return res, err
+	}
+	defer archivo.Close()
+
+	procesos := make(map[uint32]*pcb.T_PCB)
+	scanner := bufio.NewScanner(archivo)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for linea := 1; scanner.Scan(); linea++ {
+		var paso traza.T_Paso
+		if err := json.Unmarshal(scanner.Bytes(), &paso); err != nil {
+			return res, fmt.Errorf("%s:%d: %v", ruta, linea, err)
+		}
+
+		proceso, ok := procesos[paso.PID]
+		if !ok {
+			proceso = procesoNuevo(paso.PID)
+			procesos[paso.PID] = proceso
+		}
+
+		reportar := func(format string, args ...any) {
+			fmt.Printf("%s:%d: PID %d, PC %d, %s: %s\n", ruta, linea, paso.PID, paso.PC, paso.Opcode, fmt.Sprintf(format, args...))
+		}
+
+		// Lo que cambió entre instrucciones no lo hizo CPU: se toma el valor de la traza y se sigue
+		if proceso.PC != paso.PC {
+			reportar("el PC quedó en %d y la traza sigue en %d (cambio externo)", proceso.PC, paso.PC)
+			cicloInstruccion.EscribirRegistro(proceso, "PC", paso.PC)
+			res.externos++
+		}
+		for registro, cambio := range paso.Registros {
+			if actual := cicloInstruccion.LeerRegistro(proceso, registro); registro != "PC" && actual != cambio.Antes {
+				reportar("%s valía %d y la traza dice %d (cambio externo)", registro, actual, cambio.Antes)
+				cicloInstruccion.EscribirRegistro(proceso, registro, cambio.Antes)
+				res.externos++
+			}
+		}
+
+		antes := cicloInstruccion.Registros(proceso)
+		esperado := make(map[string]uint32, len(antes))
+		for registro, valor := range antes {
+			esperado[registro] = valor
+		}
+		for registro, cambio := range paso.Registros {
+			esperado[registro] = cambio.Despues
+		}
+
+		if reproducible(paso) {
+			cicloInstruccion.Ejecutar(proceso, strings.Join(append([]string{paso.Opcode}, paso.Operandos...), " "))
+			pcb.EvictionFlag = false
+			res.reejecutados++
+
+			despues := cicloInstruccion.Registros(proceso)
+			for _, registro := range pcb.Registers {
+				if despues[registro] != esperado[registro] {
+					reportar("%s da %d y la traza dice %d", registro, despues[registro], esperado[registro])
+					res.divergencias++
+				}
+			}
+		}
+
+		// Se continúa desde lo que registró la traza, haya coincidido o no
+		for _, registro := range pcb.Registers {
+			cicloInstruccion.EscribirRegistro(proceso, registro, esperado[registro])
+		}
+		res.pasos++
+	}
+	return res, scanner.Err()
+}
+
+// procesoNuevo: PCB con todos los registros en cero, como lo crea kernel
+func procesoNuevo(pid uint32) *pcb.T_PCB {
+	proceso := &pcb.T_PCB{PID: pid, CPU_reg: make(map[string]interface{})}
+	for _, registro := range pcb.Registers {
+		cicloInstruccion.EscribirRegistro(proceso, registro, 0)
+	}
+	return proceso
+}
+
+// reproducible: La instrucción depende solo de registros
+func reproducible(paso traza.T_Paso) bool {
+	if !reproducibles[paso.Opcode] {
+		return false
+	}
+	for _, texto := range paso.Operandos {
+		if operando, err := cicloInstruccion.ParsearOperando(texto); err == nil && operando.Tipo == cicloInstruccion.OperandoMemoria {
+			return false
+		}
+	}
+	return true
+}
@@ -6,7 +6,11 @@ import (
 	"net/http"
 
 	cpu_api "github.com/sisoputnfrba/tp-golang/cpu/API"
+	"github.com/sisoputnfrba/tp-golang/cpu/debugger"
 	"github.com/sisoputnfrba/tp-golang/cpu/globals"
+	"github.com/sisoputnfrba/tp-golang/cpu/icache"
+	"github.com/sisoputnfrba/tp-golang/cpu/traza"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	cfg "github.com/sisoputnfrba/tp-golang/utils/config"
 	logger "github.com/sisoputnfrba/tp-golang/utils/log"
 	server "github.com/sisoputnfrba/tp-golang/utils/server-Functions"
@@ -29,6 +33,15 @@ func main() {
 	
 	fmt.Println("Configuracion CPU cargada")
 
+	// Con clock_url CPU avisa al reloj virtual de kernel cuándo está ejecutando, para que no avance en plena ráfaga
+	if globals.Configcpu.Clock_url != "" {
+		clock.UseRemote(globals.Configcpu.Clock_url)
+	}
+
+	if err := traza.Iniciar(globals.Configcpu.Trace_dir); err != nil {
+		log.Fatalf("Error al crear el directorio de trazas %v", err)
+	}
+
 	// Handlers
 	cpuRoutes := RegisteredModuleRoutes()
 
@@ -46,7 +59,21 @@ func RegisteredModuleRoutes() http.Handler {
 		RouteHandlers: map[string]http.HandlerFunc{
 			"POST /dispatch": 	cpu_api.PCB_recv,
 			"POST /interrupt": 	cpu_api.HandleInterruption,
+			"GET /health": 		cpu_api.Health,
+			"DELETE /instruction-cache": icache.HandleInvalidar,
+			// Depuración
+			"GET /debug": 					debugger.HandleEstado,
+			"POST /debug/breakpoints": 		debugger.HandleAgregarBreakpoint,
+			"DELETE /debug/breakpoints": 	debugger.HandleQuitarBreakpoint,
+			"POST /debug/step": 			debugger.HandlePaso,
+			"POST /debug/continue": 		debugger.HandleContinuar,
+			"GET /debug/registers": 		debugger.HandleLeerRegistros,
+			"PATCH /debug/registers": 		debugger.HandleEscribirRegistro,
+			"GET /debug/memory": 			debugger.HandleLeerMemoria,
 		},
 	}
+	// ModuleHandler compara la ruta exacta: las que llevan parámetros van al DefaultServeMux, que las atiende si no matchea ninguna
+	http.HandleFunc("GET /trace/{pid}", traza.HandleTraza)
+
 	return moduleHandler
 }
@@ -0,0 +1,307 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/sisoputnfrba/tp-golang/cpu/cicloInstruccion"
+	"github.com/sisoputnfrba/tp-golang/cpu/globals"
+	mmu "github.com/sisoputnfrba/tp-golang/cpu/mmu"
+	solicitudesmemoria "github.com/sisoputnfrba/tp-golang/cpu/solicitudesMemoria"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+)
+
+type T_Breakpoint struct {
+	PID 	uint32 	`json:"pid"`
+	PC 		uint32 	`json:"pc"`
+}
+
+var (
+	breakpoints 	= make(map[T_Breakpoint]bool)
+	pasoAPaso 		bool 			// frenar antes de la próxima instrucción, sea cual sea
+	detenido 		*pcb.T_PCB 		// proceso frenado esperando un comando (nil si CPU está ejecutando o libre)
+	reanudar 		chan struct{}
+	mutex 			sync.Mutex
+)
+
+/**
+ * Esperar: Lo llama el ciclo de instrucción antes de cada instrucción. Si hay un breakpoint en (PID, PC) o se pidió
+ * avanzar de a un paso, el proceso queda frenado hasta que llegue STEP o CONTINUE.
+ * Se le avisa a kernel al frenar y al reanudar, para que mientras tanto no corran el quantum ni el límite de CPU.
+
+ * @param currentPCB: proceso en ejecución
+*/
+func Esperar(currentPCB *pcb.T_PCB) {
+	mutex.Lock()
+	if !pasoAPaso && !breakpoints[T_Breakpoint{PID: currentPCB.PID, PC: currentPCB.PC}] {
+		mutex.Unlock()
+		return
+	}
+	pasoAPaso = false
+	detenido = currentPCB
+	reanudar = make(chan struct{})
+	espera := reanudar
+	mutex.Unlock()
+
+	log.Printf("PID: %d - DEBUG - Detenido - Program Counter: %d - Registros: %v", currentPCB.PID, currentPCB.PC, currentPCB.CPU_reg)
+	avisarKernel(currentPCB.PID, http.MethodPut)
+	// Frenado no ejecuta: el reloj virtual puede avanzar para el resto de los módulos
+	clock.Idle()
+	<-espera
+	clock.Busy()
+	avisarKernel(currentPCB.PID, http.MethodDelete)
+}
+
+// avisarKernel: PUT o DELETE /process/{pid}/breakpoint en kernel, al frenar o reanudar al proceso
+func avisarKernel(pid uint32, metodo string) {
+	url := fmt.Sprintf("http://%s:%d/process/%d/breakpoint", globals.Configcpu.IP_kernel, globals.Configcpu.Port_kernel, pid)
+	req, err := http.NewRequest(metodo, url, nil)
+	if err != nil {
+		log.Printf("PID: %d - DEBUG - No se pudo avisar a kernel: %v", pid, err)
+		return
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Printf("PID: %d - DEBUG - No se pudo avisar a kernel: %v", pid, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("PID: %d - DEBUG - Kernel rechazó el aviso: %s", pid, resp.Status)
+	}
+}
+
+// continuar: Despierta al proceso frenado. Con paso en true se vuelve a frenar antes de la instrucción siguiente.
+func continuar(paso bool) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if detenido == nil {
+		return false
+	}
+	pasoAPaso = paso
+	detenido = nil
+	close(reanudar)
+	return true
+}
+
+// --------------------- ENDPOINTS ------------------------
+
+type T_Estado struct {
+	Detenido 		bool 					`json:"paused"`
+	PID 			uint32 					`json:"pid"`
+	PC 				uint32 					`json:"pc"`
+	Registros 		map[string]interface{} 	`json:"registers,omitempty"`
+	Breakpoints 	[]T_Breakpoint 			`json:"breakpoints"`
+}
+
+/**
+ * HandleEstado: GET /debug. Proceso frenado (si hay), sus registros y los breakpoints.
+*/
+func HandleEstado(w http.ResponseWriter, r *http.Request) {
+	mutex.Lock()
+	estado := T_Estado{Breakpoints: make([]T_Breakpoint, 0, len(breakpoints))}
+	for breakpoint := range breakpoints {
+		estado.Breakpoints = append(estado.Breakpoints, breakpoint)
+	}
+	if detenido != nil {
+		estado.Detenido = true
+		estado.PID = detenido.PID
+		estado.PC = detenido.PC
+		// Copia: al reanudar, el ciclo de instrucción vuelve a escribir los registros mientras se codifica la respuesta
+		estado.Registros = maps.Clone(detenido.CPU_reg)
+	}
+	mutex.Unlock()
+
+	sort.Slice(estado.Breakpoints, func(i, j int) bool {
+		if estado.Breakpoints[i].PID != estado.Breakpoints[j].PID {
+			return estado.Breakpoints[i].PID < estado.Breakpoints[j].PID
+		}
+		return estado.Breakpoints[i].PC < estado.Breakpoints[j].PC
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(estado)
+}
+
+/**
+ * HandleAgregarBreakpoint: POST /debug/breakpoints con {"pid": 1, "pc": 3}
+*/
+func HandleAgregarBreakpoint(w http.ResponseWriter, r *http.Request) {
+	var breakpoint T_Breakpoint
+	if err := json.NewDecoder(r.Body).Decode(&breakpoint); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	mutex.Lock()
+	breakpoints[breakpoint] = true
+	mutex.Unlock()
+
+	log.Printf("PID: %d - DEBUG - Breakpoint agregado - Program Counter: %d", breakpoint.PID, breakpoint.PC)
+	w.WriteHeader(http.StatusCreated)
+}
+
+/**
+ * HandleQuitarBreakpoint: DELETE /debug/breakpoints?pid=1&pc=3. Sin pc se quitan todos los del proceso.
+*/
+func HandleQuitarBreakpoint(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.ParseUint(r.URL.Query().Get("pid"), 10, 32)
+	if err != nil {
+		http.Error(w, "pid inválido", http.StatusBadRequest)
+		return
+	}
+
+	pcTexto := r.URL.Query().Get("pc")
+	pc, err := strconv.ParseUint(pcTexto, 10, 32)
+	if pcTexto != "" && err != nil {
+		http.Error(w, "pc inválido", http.StatusBadRequest)
+		return
+	}
+
+	mutex.Lock()
+	for breakpoint := range breakpoints {
+		if breakpoint.PID == uint32(pid) && (pcTexto == "" || breakpoint.PC == uint32(pc)) {
+			delete(breakpoints, breakpoint)
+		}
+	}
+	mutex.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+/**
+ * HandlePaso: POST /debug/step. Ejecuta una instrucción del proceso frenado y lo vuelve a frenar.
+ * Si no hay ninguno frenado, frena al que esté ejecutando antes de su próxima instrucción.
+*/
+func HandlePaso(w http.ResponseWriter, r *http.Request) {
+	if !continuar(true) {
+		mutex.Lock()
+		pasoAPaso = true
+		mutex.Unlock()
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+/**
+ * HandleContinuar: POST /debug/continue. El proceso frenado sigue hasta el próximo breakpoint o desalojo.
+*/
+func HandleContinuar(w http.ResponseWriter, r *http.Request) {
+	mutex.Lock()
+	pasoAPaso = false
+	mutex.Unlock()
+
+	if !continuar(false) {
+		http.Error(w, "No hay ningún proceso detenido", http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// procesoDetenido: Proceso frenado o, si no hay, responde 409. Solo se tocan registros y memoria con el proceso quieto.
+func procesoDetenido(w http.ResponseWriter) *pcb.T_PCB {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if detenido == nil {
+		http.Error(w, "No hay ningún proceso detenido", http.StatusConflict)
+	}
+	return detenido
+}
+
+/**
+ * HandleLeerRegistros: GET /debug/registers. Registros del proceso frenado.
+*/
+func HandleLeerRegistros(w http.ResponseWriter, r *http.Request) {
+	proceso := procesoDetenido(w)
+	if proceso == nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cicloInstruccion.Registros(proceso))
+}
+
+type T_EscribirRegistro struct {
+	Registro 	string 	`json:"register"`
+	Valor 		uint32 	`json:"value"`
+}
+
+/**
+ * HandleEscribirRegistro: PATCH /debug/registers con {"register": "AX", "value": 5}. El valor se trunca al ancho del registro.
+*/
+func HandleEscribirRegistro(w http.ResponseWriter, r *http.Request) {
+	var request T_EscribirRegistro
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !pcb.IsRegister(request.Registro) {
+		http.Error(w, "Registro desconocido: "+request.Registro, http.StatusBadRequest)
+		return
+	}
+
+	proceso := procesoDetenido(w)
+	if proceso == nil {
+		return
+	}
+
+	cicloInstruccion.EscribirRegistro(proceso, request.Registro, request.Valor)
+	log.Printf("PID: %d - DEBUG - Registro %s = %d", proceso.PID, request.Registro, request.Valor)
+	w.WriteHeader(http.StatusOK)
+}
+
+type T_Memoria struct {
+	Direccion 	int 	`json:"address"`
+	Bytes 		[]int 	`json:"bytes"`
+}
+
+/**
+ * HandleLeerMemoria: GET /debug/memory?address=16&size=4. Lee memoria del proceso frenado por dirección lógica, a través de la MMU.
+*/
+func HandleLeerMemoria(w http.ResponseWriter, r *http.Request) {
+	direccion, errDireccion := strconv.Atoi(r.URL.Query().Get("address"))
+	tamanio, errTamanio := strconv.Atoi(r.URL.Query().Get("size"))
+	if errDireccion != nil || errTamanio != nil || direccion < 0 || tamanio <= 0 {
+		http.Error(w, "address y size deben ser números positivos", http.StatusBadRequest)
+		return
+	}
+
+	proceso := procesoDetenido(w)
+	if proceso == nil {
+		return
+	}
+
+	tamTotal := mmu.PedirTamTablaPaginas(int(proceso.PID)) * mmu.SolicitarTamPagina()
+	if direccion+tamanio > tamTotal {
+		http.Error(w, "La dirección está fuera del proceso (tamaño "+strconv.Itoa(tamTotal)+")", http.StatusBadRequest)
+		return
+	}
+
+	direcsFisicas := mmu.ObtenerDireccionesFisicas(direccion, tamanio, int(proceso.PID))
+	datos := solicitudesmemoria.SolicitarLectura(direcsFisicas, int(proceso.PID))
+
+	respuesta := T_Memoria{Direccion: direccion, Bytes: make([]int, 0, len(datos))}
+	for _, dato := range datos {
+		respuesta.Bytes = append(respuesta.Bytes, int(dato))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(respuesta)
+}
+
+// Olvidar: Quita los breakpoints de un proceso que terminó
+func Olvidar(pid uint32) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for breakpoint := range breakpoints {
+		if breakpoint.PID == pid {
+			delete(breakpoints, breakpoint)
+		}
+	}
+}
@@ -13,17 +13,6 @@ import (
 var Configcpu *T_CPU
 var MemDelay int
 
-var EvictionReasons = map[string]struct{}{
-		"EXIT":          		{},
-		"BLOCKED_IO_GEN": 		{},
-		"BLOCKED_IO_STDIN":		{},
-		"BLOCKED_IO_STDOUT":	{},
-		"BLOCKED_IO_DIALFS":    {},
-		"OUT_OF_MEMORY": 		{},
-		"WAIT":		 			{},
-		"SIGNAL":		 		{},
-	}
-
 type T_CPU struct {
 	Port               int    `json:"port"`
 	IP_memory          string `json:"ip_memory"`
@@ -32,6 +21,11 @@ type T_CPU struct {
 	Port_kernel        int    `json:"port_kernel"`
 	Number_felling_tlb int    `json:"number_felling_tlb"`
 	Algorithm_tlb      string `json:"algorithm_tlb"`
+	Stack_size         int    `json:"stack_size"`
+	Instruction_cache_size int `json:"instruction_cache_size"`
+	Prefetch_window    int    `json:"prefetch_window"`
+	Trace_dir          string `json:"trace_dir"`
+	Clock_url          string `json:"clock_url"`
 }
 
 var CurrentJob *pcb.T_PCB
@@ -55,10 +49,7 @@ type InterfaceController struct {
 	Controller chan bool
 }
 
-type DireccionTamanio struct {
-	DireccionFisica int
-	Tamanio         int
-}
+type DireccionTamanio = pcb.DireccionTamanio
 
 func PasarAInt(cadena string) int {
 	num, err := strconv.Atoi(cadena)
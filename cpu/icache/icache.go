@@ -0,0 +1,145 @@
+package icache
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/sisoputnfrba/tp-golang/cpu/globals"
+	"github.com/sisoputnfrba/tp-golang/utils/generics"
+)
+
+// Cantidad de instrucciones que se traen por bloque si la configuración no lo indica
+const ventanaPorDefecto = 8
+
+type clave struct {
+	pid uint32
+	pc  uint32
+}
+
+// Instrucción cacheada junto con su origen en el script (etiqueta y línea), para los logs de FETCH
+type T_Entrada struct {
+	Instruccion 	string
+	Origen 			string
+}
+
+// Bloque de instrucciones que devuelve memoria
+type BloqueInstrucciones_BRS struct {
+	Desde 			int 		`json:"desde"`
+	Instrucciones 	[]string 	`json:"instrucciones"`
+	Origenes 		[]string 	`json:"origenes"`
+}
+
+var (
+	entradas 	= make(map[clave]T_Entrada)
+	orden 		[]clave // orden de llegada, para reemplazar por FIFO como la TLB
+	mutex 		sync.Mutex
+)
+
+// Habilitada: La caché se usa solo si tiene al menos una entrada
+func Habilitada() bool {
+	return globals.Configcpu.Instruction_cache_size > 0
+}
+
+/**
+ * Buscar: Busca la instrucción de un proceso en la caché y, si no está, trae de memoria un bloque que empieza en ella
+
+ * @param pid: proceso
+ * @param pc: número de instrucción
+ * @return T_Entrada: instrucción y su origen
+ * @return error: si memoria no devolvió la instrucción
+*/
+func Buscar(pid uint32, pc uint32) (T_Entrada, error) {
+	mutex.Lock()
+	entrada, ok := entradas[clave{pid, pc}]
+	mutex.Unlock()
+
+	if ok {
+		log.Printf("PID: %d - CACHE HIT - Program Counter: %d", pid, pc)
+		return entrada, nil
+	}
+	log.Printf("PID: %d - CACHE MISS - Program Counter: %d", pid, pc)
+
+	bloque, err := pedirBloque(pid, pc)
+	if err != nil {
+		return T_Entrada{}, err
+	}
+	cargar(pid, bloque)
+
+	return T_Entrada{Instruccion: bloque.Instrucciones[0], Origen: bloque.Origenes[0]}, nil
+}
+
+// pedirBloque: Pide a memoria la ventana de instrucciones configurada a partir de pc
+func pedirBloque(pid uint32, pc uint32) (BloqueInstrucciones_BRS, error) {
+	ventana := globals.Configcpu.Prefetch_window
+	if ventana <= 0 {
+		ventana = ventanaPorDefecto
+	}
+	// No tiene sentido traer más instrucciones de las que entran en la caché
+	ventana = min(ventana, globals.Configcpu.Instruction_cache_size)
+
+	url := fmt.Sprintf("http://%s:%d/instrucciones/bloque?pid=%d&pc=%d&cantidad=%d", globals.Configcpu.IP_memory, globals.Configcpu.Port_memory, pid, pc, ventana)
+
+	var bloque BloqueInstrucciones_BRS
+	if err := generics.DoRequest("GET", url, nil, &bloque); err != nil {
+		return BloqueInstrucciones_BRS{}, err
+	}
+	if len(bloque.Instrucciones) == 0 || len(bloque.Origenes) != len(bloque.Instrucciones) {
+		return BloqueInstrucciones_BRS{}, fmt.Errorf("memoria no devolvió la instrucción %d del PID %d", pc, pid)
+	}
+	return bloque, nil
+}
+
+// cargar: Guarda el bloque en la caché, reemplazando por FIFO las entradas más viejas si no hay lugar
+func cargar(pid uint32, bloque BloqueInstrucciones_BRS) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for i, instruccion := range bloque.Instrucciones {
+		k := clave{pid, uint32(bloque.Desde + i)}
+		if _, existe := entradas[k]; !existe {
+			for len(orden) >= globals.Configcpu.Instruction_cache_size {
+				delete(entradas, orden[0])
+				orden = orden[1:]
+			}
+			orden = append(orden, k)
+		}
+		entradas[k] = T_Entrada{Instruccion: instruccion, Origen: bloque.Origenes[i]}
+	}
+}
+
+/**
+ * Invalidar: Descarta las instrucciones cacheadas de un proceso (finalizó o se recargó su código)
+
+ * @param pid: proceso
+*/
+func Invalidar(pid uint32) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	restantes := orden[:0]
+	for _, k := range orden {
+		if k.pid == pid {
+			delete(entradas, k)
+		} else {
+			restantes = append(restantes, k)
+		}
+	}
+	orden = restantes
+	log.Printf("PID: %d - Caché de instrucciones invalidada", pid)
+}
+
+/**
+ * HandleInvalidar: DELETE /instruction-cache?pid=N. Lo usa kernel al finalizar un proceso o cargar su código.
+*/
+func HandleInvalidar(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.ParseUint(r.URL.Query().Get("pid"), 10, 32)
+	if err != nil {
+		http.Error(w, "pid inválido", http.StatusBadRequest)
+		return
+	}
+	Invalidar(uint32(pid))
+	w.WriteHeader(http.StatusOK)
+}
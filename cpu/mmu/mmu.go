@@ -10,6 +10,7 @@ import (
 	"github.com/sisoputnfrba/tp-golang/cpu/globals"
 	solicitudesmemoria "github.com/sisoputnfrba/tp-golang/cpu/solicitudesMemoria"
 	"github.com/sisoputnfrba/tp-golang/cpu/tlb"
+	"github.com/sisoputnfrba/tp-golang/cpu/traza"
 	"github.com/sisoputnfrba/tp-golang/utils/pcb"
 	"github.com/sisoputnfrba/tp-golang/utils/slice"
 )
@@ -72,7 +73,6 @@ func PedirTamTablaPaginas(pid int) int {
 	tamTablaString := string(tamTabla)
 	tamTablaInt := globals.PasarAInt(tamTablaString)
 
-	fmt.Print("Dato recibido en Int ", tamTablaInt)
 	return tamTablaInt
 
 }
@@ -122,7 +122,6 @@ func ObtenerDireccionesFisicas(direccionLogica int, tamanio int, pid int) []glob
 	numeroPagina := direccionLogica / tamPagina
 	desplazamiento := direccionLogica - numeroPagina*tamPagina
 	cantidadPaginas := (desplazamiento + tamanio) / tamPagina
-	fmt.Print("Cantidad de páginas: ", cantidadPaginas)
 
 	var frame int
 	var tamanioTotal int
@@ -132,13 +131,14 @@ func ObtenerDireccionesFisicas(direccionLogica int, tamanio int, pid int) []glob
 
 	if PedirTamTablaPaginas(pid) == 0 {
 		tamanioTotal = desplazamiento + tamanio
-		fmt.Print("Entre por primera vez a la tabla, tamaño", tamanioTotal)
 	} else {
 		if tlb.BuscarEnTLB(pid, numeroPagina) {
 			log.Printf("PID: %d - TLB HIT - Pagina: %d", pid, numeroPagina)
+			traza.TLB(numeroPagina, true)
 			frame = tlb.FrameEnTLB(pid, numeroPagina)
 		} else {
 			log.Printf("PID: %d - TLB MISS - Pagina: %d", pid, numeroPagina)
+			traza.TLB(numeroPagina, false)
 			frame = Frame_rcv(globals.CurrentJob, numeroPagina)
 			tlb.ActualizarTLB(pid, numeroPagina, frame)
 		}
@@ -146,7 +146,6 @@ func ObtenerDireccionesFisicas(direccionLogica int, tamanio int, pid int) []glob
 	}
 
 	if tamanioTotal > PedirTamTablaPaginas(pid)*tamPagina {
-		fmt.Print("Voy a Solicitar Resize")
 		solicitudesmemoria.Resize(tamanioTotal)
 	}
 
@@ -158,24 +157,19 @@ func ObtenerDireccionesFisicas(direccionLogica int, tamanio int, pid int) []glob
 		tamanioRestante := tamanio - (tamPagina - desplazamiento)
 
 		for i := 1; i < cantidadPaginas; i++ {
-			fmt.Println("Tamaño restante: ", tamanioRestante)
-			fmt.Println("Desplazamiento: ", desplazamiento)
-			fmt.Println("Numero de página: ", numeroPagina)
-			fmt.Println("El valor de i: ", i)
-			fmt.Println("Cantidad de páginas: ", cantidadPaginas)
 			if i == cantidadPaginas - 1 {
 				//Ultima pagina teniendo en cuenta el tamanio
 				numeroPagina++
 				if tlb.BuscarEnTLB(pid, numeroPagina) {
 					log.Printf("PID: %d - TLB HIT - Pagina: %d", pid, numeroPagina)
+					traza.TLB(numeroPagina, true)
 					frame = tlb.FrameEnTLB(pid, numeroPagina)
-					fmt.Printf("BUSQUE EN TLB PARA EL PID %d LA PAG %d EL FRAME %d ", pid, numeroPagina, frame)
 
 				} else {
 					log.Printf("PID: %d - TLB MISS - Pagina: %d", pid, numeroPagina)
+					traza.TLB(numeroPagina, false)
 					frame = Frame_rcv(globals.CurrentJob, numeroPagina)
 					tlb.ActualizarTLB(pid, numeroPagina, frame)
-					fmt.Printf("Busco FRAME MEMORIA para el PID %d Y EL FRAME ES %d ", pid, frame)
 				}
 				slice.Push(&direccion_y_tamanio, globals.DireccionTamanio{DireccionFisica: frame * tamPagina, Tamanio: tamanioRestante})
 
@@ -183,13 +177,13 @@ func ObtenerDireccionesFisicas(direccionLogica int, tamanio int, pid int) []glob
 				numeroPagina++
 				if tlb.BuscarEnTLB(pid, numeroPagina) {
 					log.Printf("PID: %d - TLB HIT - Pagina: %d", pid, numeroPagina)
+					traza.TLB(numeroPagina, true)
 					frame = tlb.FrameEnTLB(pid, numeroPagina)
-					fmt.Printf("BUSQUE EN TLB PARA EL PID %d LA PAG %d EL FRAME %d ", pid, numeroPagina, frame)
 
 				} else {
 					log.Printf("PID: %d - TLB MISS - Pagina: %d", pid, numeroPagina)
+					traza.TLB(numeroPagina, false)
 					frame = Frame_rcv(globals.CurrentJob, numeroPagina)
-					fmt.Printf("Busco FRAME MEMORIA para el PID %d Y EL FRAME ES %d ", pid, frame)
 					tlb.ActualizarTLB(pid, numeroPagina, frame)
 				}
 				slice.Push(&direccion_y_tamanio, globals.DireccionTamanio{DireccionFisica: frame * tamPagina, Tamanio: tamPagina})
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/sisoputnfrba/tp-golang/cpu/globals"
+	"github.com/sisoputnfrba/tp-golang/cpu/traza"
 )
 
 // Peticion para RESIZE de memoria (DESDE CPU A MEMORIA)
@@ -91,17 +92,12 @@ func SolicitarEscritura(direccionesTamanios []globals.DireccionTamanio, valorAEs
 
 	respuestaSinComillas := strings.Trim(respuestaEnString, `"`)
 
-	fmt.Println("Respuesta de memoria: ", respuestaSinComillas)
-	fmt.Println("Valor a escribir bytes: ", valorAEscribir)
-	valorAEscribirEnString := string(valorAEscribir)
-	fmt.Println("Valor a escribir en string: ", valorAEscribirEnString)
-
 	if respuestaSinComillas != "OK" {
 		fmt.Println("Se produjo un error al escribir", respuestaSinComillas)
 	} else {
 		for _, df := range direccionesTamanios {
 			cantEscrita := 0
-			fmt.Println("Valor a escribir: ", valorAEscribir)
+			traza.Acceso("ESCRIBIR", df.DireccionFisica, df.Tamanio)
 			log.Printf("PID: %d - Acción: ESCRIBIR - Dirección Física: %d - Valor: %b", pid, df.DireccionFisica, valorAEscribir[cantEscrita:df.Tamanio])
 			cantEscrita += df.Tamanio
 		}
@@ -136,8 +132,6 @@ func SolicitarLectura(direccionesFisicas []globals.DireccionTamanio, pid int) []
 		return []byte("error")
 	}
 
-	fmt.Println("Solicito lectura de memoria", jsonDirecYTamanio)
-
 	leerMemoria.Header.Set("Content-Type", "application/json")
 	respuesta, err := cliente.Do(leerMemoria)
 	if err != nil {
@@ -152,11 +146,10 @@ func SolicitarLectura(direccionesFisicas []globals.DireccionTamanio, pid int) []
 	if err != nil {
 		return []byte("error al deserializar la respuesta")
 	}
-	fmt.Println("Direcciones fisicas: ", direccionesFisicas)
 
 	for i, df := range direccionesFisicas {
 		contenido := bodyResponseLeer.Contenido[i]
-		fmt.Println("Contenido leido: ", contenido)
+		traza.Acceso("LEER", df.DireccionFisica, df.Tamanio)
 		log.Printf("PID: %d - Acción: LEER - Dirección Física: %d - Valor: %b", pid, df.DireccionFisica, contenido)
 	}
 
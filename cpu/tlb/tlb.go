@@ -1,8 +1,6 @@
 package tlb
 
 import (
-	"fmt"
-
 	"github.com/sisoputnfrba/tp-golang/cpu/globals"
 )
 
@@ -71,21 +69,11 @@ func ActualizarTLB(pid, pagina, marco int) {
 						pid: {Pagina: pagina, Marco: marco},
 					}
 					CurrentTLB = append(CurrentTLB, nuevoElemento)
-					fmt.Printf("Se agregó la entrada %d a la TLB", CurrentTLB)
-					fmt.Println("LA TLB QUEDO ASI: ")
-					for i := range CurrentTLB {
-						fmt.Println(CurrentTLB[i])
-					}
 				} else {
 					// Remover el primer elemento (FIFO) y agregar el nuevo
 					CurrentTLB = append(CurrentTLB[1:], map[int]Pagina_marco{
 						pid: {Pagina: pagina, Marco: marco},
 					})
-					fmt.Printf("Se agregó la entrada %d a la TLB", CurrentTLB)
-					fmt.Println("LA TLB QUEDO ASI: ")
-					for i := range CurrentTLB {
-						fmt.Println(CurrentTLB[i])
-					}
 				}
 			}
 
@@ -109,12 +97,6 @@ func ActualizarTLB(pid, pagina, marco int) {
 				CurrentTLB = append(CurrentTLB[:indice], CurrentTLB[indice+1:]...)
 				CurrentTLB = append(CurrentTLB, map[int]Pagina_marco{pid: {Pagina: pagina, Marco: marco}})
 			}
-
-			// Imprimir la TLB
-			fmt.Println("LA TLB QUEDO ASI: ")
-			for i := range CurrentTLB {
-				fmt.Println(CurrentTLB[i])
-			}
 		}
 	}
 }
@@ -143,10 +125,5 @@ func ActualizarOrdenDeAcceso(pid, pagina, marco int) {
 			pid: {Pagina: pagina, Marco: marco},
 		}
 		CurrentTLB = append(CurrentTLB, nuevoElemento)
-		fmt.Printf("Se agregó la entrada %d a la TLB\n", pid)
-		fmt.Println("LA TLB QUEDO ASI: ")
-		for i := range CurrentTLB {
-			fmt.Println(CurrentTLB[i])
-		}
 	}
 }
@@ -0,0 +1,201 @@
+package traza
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Cambio de un registro durante una instrucción
+type T_Cambio struct {
+	Antes 		uint32 	`json:"before"`
+	Despues 	uint32 	`json:"after"`
+}
+
+// Acceso a memoria hecho por una instrucción (dirección física)
+type T_Acceso struct {
+	Accion 		string 	`json:"action"` // LEER o ESCRIBIR
+	Direccion 	int 	`json:"address"`
+	Tamanio 	int 	`json:"size"`
+}
+
+// Consulta a la TLB hecha por una instrucción
+type T_ConsultaTLB struct {
+	Pagina 	int 	`json:"page"`
+	Hit 	bool 	`json:"hit"`
+}
+
+// Una instrucción ejecutada: una línea del archivo de traza
+type T_Paso struct {
+	PID 		uint32 				`json:"pid"`
+	PC 			uint32 				`json:"pc"`
+	Opcode 		string 				`json:"opcode"`
+	Operandos 	[]string 			`json:"operands"`
+	Registros 	map[string]T_Cambio `json:"registers"`
+	Memoria 	[]T_Acceso 			`json:"memory,omitempty"`
+	TLB 		[]T_ConsultaTLB 	`json:"tlb,omitempty"`
+}
+
+var (
+	directorio 	string 						// directorio de esta ejecución de CPU ("" si la traza está deshabilitada)
+	archivos 	= make(map[uint32]*os.File)
+	actual 		*T_Paso 					// instrucción en curso (nil fuera de Comenzar / Terminar)
+	antes 		map[string]uint32
+	mutex 		sync.Mutex
+)
+
+/**
+ * Iniciar: Habilita la traza. Cada ejecución de CPU escribe en su propio subdirectorio, un archivo JSON lines por proceso.
+
+ * @param base: directorio de trazas de la configuración ("" la deja deshabilitada)
+ * @return error: si no se pudo crear el directorio
+*/
+func Iniciar(base string) error {
+	if base == "" {
+		return nil
+	}
+
+	dir := filepath.Join(base, time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	directorio = dir
+	mutex.Unlock()
+
+	log.Printf("Traza de ejecución en %s", dir)
+	return nil
+}
+
+// Habilitada: Hay traza si se configuró un directorio
+func Habilitada() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return directorio != ""
+}
+
+/**
+ * Comenzar: Abre el paso de una instrucción. Lo llama el ciclo de instrucción antes de ejecutarla.
+
+ * @param pid: proceso en ejecución
+ * @param pc: instrucción a ejecutar
+ * @param registros: valores de los registros antes de ejecutarla
+*/
+func Comenzar(pid uint32, pc uint32, registros map[string]uint32) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if directorio == "" {
+		return
+	}
+	actual = &T_Paso{PID: pid, PC: pc, Operandos: []string{}, Registros: make(map[string]T_Cambio)}
+	antes = registros
+}
+
+// Instruccion: Anota el código de operación y los operandos de la instrucción en curso
+func Instruccion(opcode string, operandos []string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if actual == nil {
+		return
+	}
+	actual.Opcode = opcode
+	actual.Operandos = append(actual.Operandos, operandos...)
+}
+
+// Acceso: Anota las direcciones físicas que leyó o escribió la instrucción en curso
+func Acceso(accion string, direccion int, tamanio int) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if actual == nil {
+		return
+	}
+	actual.Memoria = append(actual.Memoria, T_Acceso{Accion: accion, Direccion: direccion, Tamanio: tamanio})
+}
+
+// TLB: Anota un acierto o fallo de TLB de la instrucción en curso
+func TLB(pagina int, hit bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if actual == nil {
+		return
+	}
+	actual.TLB = append(actual.TLB, T_ConsultaTLB{Pagina: pagina, Hit: hit})
+}
+
+/**
+ * Terminar: Cierra el paso con los registros que cambiaron y lo agrega al archivo del proceso
+
+ * @param registros: valores de los registros después de ejecutar la instrucción
+*/
+func Terminar(registros map[string]uint32) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if actual == nil {
+		return
+	}
+	paso := actual
+	actual = nil
+
+	for registro, despues := range registros {
+		if antes[registro] != despues {
+			paso.Registros[registro] = T_Cambio{Antes: antes[registro], Despues: despues}
+		}
+	}
+
+	if err := escribir(paso); err != nil {
+		log.Printf("PID: %d - No se pudo escribir la traza: %v", paso.PID, err)
+	}
+}
+
+// escribir: Agrega el paso al archivo de su proceso. Se llama con el mutex tomado.
+func escribir(paso *T_Paso) error {
+	archivo, ok := archivos[paso.PID]
+	if !ok {
+		var err error
+		archivo, err = os.Create(ruta(paso.PID))
+		if err != nil {
+			return err
+		}
+		archivos[paso.PID] = archivo
+	}
+	return json.NewEncoder(archivo).Encode(paso)
+}
+
+func ruta(pid uint32) string {
+	return filepath.Join(directorio, fmt.Sprintf("pid-%d.jsonl", pid))
+}
+
+/**
+ * HandleTraza: GET /trace/{pid}. Devuelve la traza del proceso en esta ejecución de CPU, en JSON lines.
+*/
+func HandleTraza(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.ParseUint(r.PathValue("pid"), 10, 32)
+	if err != nil {
+		http.Error(w, "pid inválido", http.StatusBadRequest)
+		return
+	}
+
+	mutex.Lock()
+	if directorio == "" {
+		mutex.Unlock()
+		http.Error(w, "La traza está deshabilitada (trace_dir en la configuración de CPU)", http.StatusNotFound)
+		return
+	}
+	contenido, err := os.ReadFile(ruta(uint32(pid)))
+	mutex.Unlock()
+
+	if err != nil {
+		http.Error(w, "No hay traza para el PID "+strconv.FormatUint(pid, 10), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Write(contenido)
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/sisoputnfrba/tp-golang/entradasalida/globals"
 	ioutils "github.com/sisoputnfrba/tp-golang/entradasalida/utils"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 )
 
 /**
@@ -338,7 +339,7 @@ func TruncateFile(pid int, nombreArchivo string, tamanioDeseado int) { //revisar
 				log.Println("ARCHIVO - ", nombreArchivo, " tiene un tamaño de ", archivoFinal.Size, " y comienza en el bloque ", archivoFinal.InitialBlock)
 				log.Println("FCB - ", nombreArchivo, " tiene un tamaño de ", globals.Fcbs[nombreArchivo].Size, " y comienza en el bloque ", globals.Fcbs[nombreArchivo].InitialBlock)
 
-				time.Sleep(time.Duration(globals.ConfigIO.Dialfs_compaction_delay)) //
+				clock.Sleep(time.Duration(globals.ConfigIO.Dialfs_compaction_delay)) //
 
 				// No entra el archivo pero alcanza la cantidad de bloques libres
 			} else if ioutils.ContadorDeEspaciosLibres() >= tamFinalEnBloques {
@@ -444,5 +445,5 @@ func Compactar() {
 	ioutils.ActualizarBloques()
 	ioutils.ActualizarBitmap()
 
-	time.Sleep(time.Duration(globals.ConfigIO.Dialfs_compaction_delay))
+	clock.Sleep(time.Duration(globals.ConfigIO.Dialfs_compaction_delay))
 }
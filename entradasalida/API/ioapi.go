@@ -14,6 +14,7 @@ import (
 	"github.com/sisoputnfrba/tp-golang/utils/device"
 	"github.com/sisoputnfrba/tp-golang/utils/generics"
 	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 )
 
 type CantUnidadesTrabajo struct {
@@ -26,6 +27,7 @@ func HandshakeKernel(nombre string) error {
 		InterfaceType: globals.ConfigIO.Type,
 		InterfaceIP:   globals.ConfigIO.Ip,
 		InterfacePort: globals.ConfigIO.Port,
+		InterfacePool: globals.ConfigIO.Pool,
 	}
 
 	jsonData, err := json.Marshal(genInterface)
@@ -48,8 +50,25 @@ func HandshakeKernel(nombre string) error {
 	return nil
 }
 
+/**
+ * DisconnectKernel: Avisa a kernel que la interfaz se desconecta, para que la dé de baja
+ */
+func DisconnectKernel(nombre string) error {
+	url := fmt.Sprintf("http://%s:%d/io/%s", globals.ConfigIO.Ip_kernel, globals.ConfigIO.Port_kernel, nombre)
+	return generics.DoRequest("DELETE", url, nil, nil)
+}
+
+/**
+ * Health: Responde al heartbeat de kernel
+ */
+func Health(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
 // Hay que declarar los tipos de body que se van a recibir desde kernel porque por alguna razón no se puede crear un struct type dentro de una función con un tipo creado por uno mismo, están todos en globals
 
+// Cada operación es una actividad para el reloj virtual desde que llega hasta que se devuelve el PCB a kernel (ver IOWork)
 func InterfaceQueuePCB(w http.ResponseWriter, r *http.Request) {
 	switch globals.ConfigIO.Type {
 	case "GENERICA":
@@ -62,6 +81,7 @@ func InterfaceQueuePCB(w http.ResponseWriter, r *http.Request) {
 		}
 
 		fmt.Println("Nueva PCB ID: ", decodedStruct.Pcb.PID, " para usar Interfaz")
+		clock.Busy()
 		globals.Generic_QueueChannel <- decodedStruct
 
 	case "STDIN":
@@ -74,6 +94,7 @@ func InterfaceQueuePCB(w http.ResponseWriter, r *http.Request) {
 		}
 
 		fmt.Println("Nueva PCB ID: ", decodedStruct.Pcb.PID, " para usar Interfaz")
+		clock.Busy()
 		globals.Stdin_QueueChannel <- decodedStruct
 
 	case "STDOUT":
@@ -86,6 +107,7 @@ func InterfaceQueuePCB(w http.ResponseWriter, r *http.Request) {
 		}
 
 		fmt.Println("Nueva PCB ID: ", decodedStruct.Pcb.PID, " para usar Interfaz")
+		clock.Busy()
 		globals.Stdout_QueueChannel <- decodedStruct
 
 	case "DIALFS":
@@ -98,6 +120,7 @@ func InterfaceQueuePCB(w http.ResponseWriter, r *http.Request) {
 		}
 
 		fmt.Println("Nueva PCB ID: ", decodedStruct.Pcb.PID, " para usar Interfaz")
+		clock.Busy()
 		globals.DialFS_QueueChannel <- decodedStruct
 	}
 
@@ -114,6 +137,7 @@ func IOWork() {
 			IO_GEN_SLEEP(interfaceToWork.TimeToSleep, interfaceToWork.Pcb)
 			fmt.Println("Fin de bloqueo para el PID: ", interfaceToWork.Pcb.PID)
 			returnPCB(interfaceToWork.Pcb)
+			clock.Idle()
 		}
 	case "STDIN":
 		var interfaceToWork globals.StdinRead
@@ -123,6 +147,7 @@ func IOWork() {
 			IO_STDIN_READ(interfaceToWork.Pcb, interfaceToWork.DireccionesFisicas)
 			fmt.Println("Fin de bloqueo para el PID: ", interfaceToWork.Pcb.PID)
 			returnPCB(interfaceToWork.Pcb)
+			clock.Idle()
 		}
 	case "STDOUT":
 		var interfaceToWork globals.StdoutWrite
@@ -132,16 +157,18 @@ func IOWork() {
 			IO_STDOUT_WRITE(interfaceToWork.Pcb, interfaceToWork.DireccionesFisicas)
 			fmt.Println("Fin de bloqueo para el PID: ", interfaceToWork.Pcb.PID)
 			returnPCB(interfaceToWork.Pcb)
+			clock.Idle()
 		}
 
 	case "DIALFS":
 		var interfaceToWork globals.DialFSRequest
 		for {
 			interfaceToWork = <-globals.DialFS_QueueChannel
-			time.Sleep(time.Duration(globals.ConfigIO.Unit_work_time) * time.Millisecond) //! agrego esto
+			clock.Sleep(time.Duration(globals.ConfigIO.Unit_work_time) * time.Millisecond) //! agrego esto
 			IO_DIALFS(interfaceToWork)
 			fmt.Println("Fin de bloqueo para el PID: ", interfaceToWork.Pcb.PID)
 			returnPCB(interfaceToWork.Pcb)
+			clock.Idle()
 
 		}
 	}
@@ -156,7 +183,7 @@ func returnPCB(pcb pcb.T_PCB) {
 func IO_GEN_SLEEP(sleepTime int, pcb pcb.T_PCB) {
 	sleepTimeTotal := time.Duration(sleepTime*globals.ConfigIO.Unit_work_time) * time.Millisecond
 	log.Printf("PID: %d - Operacion: IO_GEN_SLEEP", pcb.PID)
-	time.Sleep(sleepTimeTotal)
+	clock.Sleep(sleepTimeTotal)
 }
 
 func IO_STDIN_READ(pcb pcb.T_PCB, direccionesFisicas []globals.DireccionTamanio) {
@@ -233,7 +260,7 @@ func IO_STDOUT_WRITE(pcb pcb.T_PCB, direccionesFisicas []globals.DireccionTamani
 	responseString := string(bytesConcatenados)
 
 	// Consumo una unidad de trabajo
-	time.Sleep(time.Duration(globals.ConfigIO.Unit_work_time) * time.Millisecond)
+	clock.Sleep(time.Duration(globals.ConfigIO.Unit_work_time) * time.Millisecond)
 
 	fmt.Print("Datos leidos: *")
 	// Escribo los datos en la salida (los muestro por pantalla)
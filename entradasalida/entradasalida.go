@@ -5,8 +5,10 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 
 	IO_api "github.com/sisoputnfrba/tp-golang/entradasalida/API"
 	"github.com/sisoputnfrba/tp-golang/entradasalida/globals"
@@ -14,6 +16,7 @@ import (
 	"github.com/sisoputnfrba/tp-golang/utils/server-Functions"
 
 	cfg "github.com/sisoputnfrba/tp-golang/utils/config"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 )
 
 func main() {
@@ -32,6 +35,11 @@ func main() {
 	cfg.VEnvIO(&globals.ConfigIO.Ip, &globals.ConfigIO.Port)
 
 	fmt.Printf("Configuración IO cargada")
+
+	// Con clock_url las demoras usan el reloj virtual de kernel en lugar del tiempo real
+	if globals.ConfigIO.Clock_url != "" {
+		clock.UseRemote(globals.ConfigIO.Clock_url)
+	}
 	
 	IORoutes := RegisteredModuleRoutes()
 
@@ -56,13 +64,22 @@ func main() {
 
 	go IO_api.IOWork()
 
-	select {}
+	// Al cerrar la interfaz se avisa a kernel para que no queden procesos esperándola
+	senial := make(chan os.Signal, 1)
+	signal.Notify(senial, os.Interrupt, syscall.SIGTERM)
+	<-senial
+
+	if err := IO_api.DisconnectKernel(nombreInterfaz); err != nil {
+		fmt.Println("Error al desconectar la interfaz de Kernel: ", err)
+	}
+	fmt.Println("Interfaz desconectada")
 }
 
 func RegisteredModuleRoutes() http.Handler {
 	moduleHandler := &server.ModuleHandler{
 		RouteHandlers: map[string]http.HandlerFunc{
 			"POST /io-operate":	IO_api.InterfaceQueuePCB,
+			"GET /health":		IO_api.Health,
 		},
 	}
 	return moduleHandler
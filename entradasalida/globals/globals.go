@@ -24,6 +24,8 @@ type T_ConfigIO struct {
 	Dialfs_block_size  		int    `json:"dialfs_block_size"`
 	Dialfs_block_count 		int    `json:"dialfs_block_count"`
 	Dialfs_compaction_delay int    `json:"dialfs_compaction_delay"`
+	Pool 					string `json:"pool"`
+	Clock_url 				string `json:"clock_url"`
 }
 
 // ----------------- Body types -----------------
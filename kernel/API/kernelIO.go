@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/sisoputnfrba/tp-golang/kernel/globals"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 	"github.com/sisoputnfrba/tp-golang/utils/device"
 	"github.com/sisoputnfrba/tp-golang/utils/pcb"
 	"github.com/sisoputnfrba/tp-golang/utils/slice"
@@ -27,9 +31,28 @@ func GetIOInterface(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	globals.IOMutex.Lock()
+	// Si la interfaz se vuelve a conectar con el mismo nombre, se reemplaza la registrada
+	removeInterface(interf.InterfaceName)
 	slice.Push(&globals.Interfaces, interf)
 
-	fmt.Printf("Interface received, type: %s, port: %d\n", interf.InterfaceType, interf.InterfacePort)
+	queue, ok := globals.IOQueues[interf.InterfaceName]
+	if ok {
+		queue.Interface = interf
+		queue.MissedHeartbeats = 0
+	} else {
+		queue = &globals.T_IOQueue{Interface: interf, RegisteredAt: clock.Now()}
+		globals.IOQueues[interf.InterfaceName] = queue
+	}
+	// Un miembro nuevo de un pool puede atender lo que ya estaba esperando en el pool
+	next, send := takeNextIfFree(queue)
+	globals.IOMutex.Unlock()
+
+	if send {
+		sendIORequest(interf, next)
+	}
+
+	fmt.Printf("Interface received, type: %s, port: %d, pool: %s\n", interf.InterfaceType, interf.InterfacePort, interf.InterfacePool)
 
 	w.WriteHeader(http.StatusOK)
 }
@@ -77,19 +100,27 @@ func ExisteInterfaz(w http.ResponseWriter, r *http.Request) {
 }
 
 /**
- * SearchDeviceByName: Busca una interfaz por su nombre.
+ * SearchDeviceByName: Busca una interfaz por su nombre. Si el nombre es el de un pool, devuelve su primer miembro
+ * (todos son del mismo tipo): el que atiende cada solicitud se elige recién cuando se envía (ver EnqueueIORequest).
 
- * @param deviceName: string -> Nombre de la interfaz a buscar.
+ * @param deviceName: string -> Nombre de la interfaz o del pool a buscar.
  * @return device.T_IOInterface -> Interfaz encontrada.
 */
 
 func SearchDeviceByName(deviceName string) (device.T_IOInterface, error) {
+	globals.IOMutex.Lock()
+	defer globals.IOMutex.Unlock()
+
 	for _, interf := range globals.Interfaces {
 		if interf.InterfaceName == deviceName {
 			fmt.Println("Interfaz encontrada: ", interf)
 			return interf, nil
 		}
 	}
+
+	if members := poolMembers(deviceName); len(members) > 0 {
+		return members[0].Interface, nil
+	}
 	return device.T_IOInterface{}, fmt.Errorf("device not found")
 }
 
@@ -129,38 +160,22 @@ type DialFSRequest struct {
 */
 
 func SolicitarGenSleep(pcb pcb.T_PCB) {
-	genSleepDataDecoded := genericInterfaceBody.(struct {
-		InterfaceName string
-		SleepTime     int
-	})
-
-	newInter, err := SearchDeviceByName(genSleepDataDecoded.InterfaceName)
-	if err != nil {
-		fmt.Printf("Device not found: %v", err)
+	syscall, ok := syscallOf(pcb)
+	if !ok {
+		return
 	}
 
 	genSleep := GenSleep{
 		Pcb:         pcb,
-		Inter:       newInter,
-		TimeToSleep: genSleepDataDecoded.SleepTime,
+		TimeToSleep: syscall.SleepTime,
 	}
 
 	globals.EnganiaPichangaMutex.Unlock()
 
-	jsonData, err := json.Marshal(genSleep)
-	if err != nil {
-		fmt.Printf("Failed to encode GenSleep request: %v", err)
-	}
-
-	url := fmt.Sprintf("http://%s:%d/io-operate", newInter.InterfaceIP, newInter.InterfacePort)
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		fmt.Printf("Failed to send PCB: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Unexpected response status: %s", resp.Status)
-	}
+	EnqueueIORequest(syscall.Interface, pcb.PID, func(inter device.T_IOInterface) any {
+		genSleep.Inter = inter
+		return genSleep
+	})
 }
 
 /**
@@ -170,46 +185,26 @@ func SolicitarGenSleep(pcb pcb.T_PCB) {
 */
 
 func SolicitarStdinRead(pcb pcb.T_PCB) {
-	stdinDataDecoded := genericInterfaceBody.(struct {
-		DireccionesFisicas []globals.DireccionTamanio
-		InterfaceName      string
-		Tamanio            int
-	})
-
-	fmt.Println("RECIBE STDIN READ: ", stdinDataDecoded)
-
-	newInter, err := SearchDeviceByName(stdinDataDecoded.InterfaceName)
-	if err != nil {
-		fmt.Printf("Device not found: %v", err)
+	syscall, ok := syscallOf(pcb)
+	if !ok {
+		return
 	}
 
+	fmt.Println("RECIBE STDIN READ: ", *syscall)
+
 	stdinRead := StdinRead{
 		Pcb:                pcb,
-		Inter:              newInter,
-		DireccionesFisicas: stdinDataDecoded.DireccionesFisicas,
+		DireccionesFisicas: syscall.Addresses,
 	}
 
 	fmt.Println("LE QUIERE MANDAR A IO: ", stdinRead)
 
 	globals.EnganiaPichangaMutex.Unlock()
 
-	jsonData, err := json.Marshal(stdinRead)
-	if err != nil {
-		fmt.Printf("Failed to encode StdinRead request: %v", err)
-	}
-
-	url := fmt.Sprintf("http://%s:%d/io-operate", newInter.InterfaceIP, newInter.InterfacePort)
-
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		fmt.Printf("Failed to send PCB: %v", err)
-	}
-
-	fmt.Println("IO STDIN FUE AVISADO POR KERNEL")
-
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Unexpected response status: %s", resp.Status)
-	}
+	EnqueueIORequest(syscall.Interface, pcb.PID, func(inter device.T_IOInterface) any {
+		stdinRead.Inter = inter
+		return stdinRead
+	})
 }
 
 /**
@@ -219,39 +214,22 @@ func SolicitarStdinRead(pcb pcb.T_PCB) {
 */
 
 func SolicitarStdoutWrite(pcb pcb.T_PCB) {
-	stdoutDataDecoded := genericInterfaceBody.(struct {
-		DireccionesFisicas []globals.DireccionTamanio
-		InterfaceName      string
-	})
-
-	newInter, err := SearchDeviceByName(stdoutDataDecoded.InterfaceName)
-	if err != nil {
-		fmt.Printf("Device not found: %v", err)
+	syscall, ok := syscallOf(pcb)
+	if !ok {
+		return
 	}
 
 	stdoutWrite := StdoutWrite{
 		Pcb:                pcb,
-		Inter:              newInter,
-		DireccionesFisicas: stdoutDataDecoded.DireccionesFisicas,
+		DireccionesFisicas: syscall.Addresses,
 	}
 
 	globals.EnganiaPichangaMutex.Unlock()
 
-	jsonData, err := json.Marshal(stdoutWrite)
-	if err != nil {
-		fmt.Printf("Failed to encode StdoutWrite request: %v", err)
-	}
-
-	url := fmt.Sprintf("http://%s:%d/io-operate", newInter.InterfaceIP, newInter.InterfacePort)
-
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		fmt.Printf("Failed to send PCB: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Unexpected response status: %s", resp.Status)
-	}
+	EnqueueIORequest(syscall.Interface, pcb.PID, func(inter device.T_IOInterface) any {
+		stdoutWrite.Inter = inter
+		return stdoutWrite
+	})
 }
 
 /**
@@ -261,185 +239,494 @@ func SolicitarStdoutWrite(pcb pcb.T_PCB) {
 */
 
 func SolicitarDialFS(pcb pcb.T_PCB) {
-	dialFsDataDecoded := genericInterfaceBody.(struct {
-		InterfaceName string
-		FileName      string
-		Size          int
-		Pointer       int
-		Address       []globals.DireccionTamanio
-		Operation     string
-	})
-
-	newInter, err := SearchDeviceByName(dialFsDataDecoded.InterfaceName)
-	if err != nil {
-		fmt.Printf("Device not found: %v", err)
+	syscall, ok := syscallOf(pcb)
+	if !ok {
+		return
 	}
 
 	dialFS := DialFSRequest{
 		Pcb:           pcb,
-		Inter:         newInter,
-		NombreArchivo: dialFsDataDecoded.FileName,
-		Tamanio:       dialFsDataDecoded.Size,
-		Puntero:       dialFsDataDecoded.Pointer,
-		Direccion:     dialFsDataDecoded.Address,
-		Operacion:     dialFsDataDecoded.Operation,
+		NombreArchivo: syscall.FileName,
+		Tamanio:       syscall.Size,
+		Puntero:       syscall.Pointer,
+		Direccion:     syscall.Addresses,
+		Operacion:     syscall.Operation,
 	}
 
 	globals.EnganiaPichangaMutex.Unlock()
 
-	jsonData, err := json.Marshal(dialFS)
-	if err != nil {
-		fmt.Printf("Failed to encode DialFS request: %v", err)
+	EnqueueIORequest(syscall.Interface, pcb.PID, func(inter device.T_IOInterface) any {
+		dialFS.Inter = inter
+		return dialFS
+	})
+}
+
+/**
+ * syscallOf: Devuelve la syscall que CPU dejó en el PCB. Si falta, finaliza al proceso en lugar de solicitar una operación vacía.
+ * Se llama con EnganiaPichangaMutex tomado; si la syscall falta, lo libera.
+
+ * @param blocked: pcb.T_PCB -> PCB bloqueado por IO.
+ * @return *pcb.T_Syscall: syscall solicitada.
+ * @return bool: true si el PCB trae una syscall.
+*/
+func syscallOf(blocked pcb.T_PCB) (*pcb.T_Syscall, bool) {
+	if blocked.Syscall != nil {
+		return blocked.Syscall, true
 	}
 
-	url := fmt.Sprintf("http://%s:%d/io-operate", newInter.InterfaceIP, newInter.InterfacePort)
+	globals.EnganiaPichangaMutex.Unlock()
+	fmt.Printf("PID: %d - Bloqueado por IO sin syscall\n", blocked.PID)
+	TerminateBlocked(blocked.PID, pcb.ReasonInvalidSyscall)
+	return nil, false
+}
+
+/*
+	RecvPCB_IO: Recibe el PCB bloqueado por IO, lo desbloquea y lo agrega a la cola de STS.
+
+	@params:
+		- w: http.ResponseWriter -> Respuesta a enviar.
+		- r: *http.Request -> Request recibido.
+
+*/
+func RecvPCB_IO(w http.ResponseWriter, r *http.Request) {
+	var received_pcb pcb.T_PCB
 
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	err := json.NewDecoder(r.Body).Decode(&received_pcb)
 	if err != nil {
-		fmt.Printf("Failed to send PCB: %v", err)
+		http.Error(w, "Failed to decode PCB", http.StatusBadRequest)
+		return
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Unexpected response status: %s", resp.Status)
+	fmt.Println("PCB que nos manda IO (Kernel): PC: ", received_pcb.PC, "PID: ", received_pcb.PID)
+
+	fmt.Println("Blocked: ", globals.Blocked)
+
+	ReleaseInterface(received_pcb.PID)
+
+	// Si el proceso fue finalizado mientras usaba la interfaz, se descarta
+	blockedJob := RemoveByID(received_pcb.PID)
+	if blockedJob.PID == 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	// Se sigue con la copia de kernel, que pudo actualizarse mientras el proceso esperaba
+	received_pcb = blockedJob
+
+	if globals.HoldIfPaused(&received_pcb) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !globals.ChangeState(&received_pcb, pcb.StateReady) {
+		http.Error(w, "Illegal state transition", http.StatusConflict)
+		return
 	}
+
+	// Si le quedó quantum sin usar (VRR), vuelve por la cola de prioridad
+	if (received_pcb.RemainingQuantum > 0) {
+		slice.Push(&globals.STS_Priority, received_pcb)
+	} else {
+		slice.Push(&globals.STS, received_pcb)
+	}
+
+	globals.STSCounter <- int(received_pcb.PID)
+
+
+	w.WriteHeader(http.StatusOK)
 }
 
-var genericInterfaceBody interface{}
+// ----------------------------- COLAS DE I/O -----------------------------
 
 /**
- * RecvData_gensleep: Recibe desde CPU la información necesaria para solicitar un GEN_SLEEP. 
+ * EnqueueIORequest: Encola una solicitud en la cola de la interfaz o, si el nombre es el de un pool, en la cola del pool.
+ * Si hay una interfaz libre que pueda atenderla, se la envía en el momento.
 
- * @param w: http.ResponseWriter -> Respuesta a enviar.
- * @param r: http.Request -> Request recibido.
+ * @param name: string -> Nombre de la interfaz o del pool destino.
+ * @param pid: uint32 -> PID del proceso que solicita la operación.
+ * @param body: func(device.T_IOInterface) any -> Arma el body de la solicitud para la interfaz que la atiende.
 */
-func RecvData_gensleep(w http.ResponseWriter, r *http.Request) {
-	var received_data struct {
-		InterfaceName string
-		SleepTime     int
+func EnqueueIORequest(name string, pid uint32, body func(device.T_IOInterface) any) {
+	request := globals.T_IORequest{PID: pid, Body: body}
+
+	globals.IOMutex.Lock()
+	queue, ok := globals.IOQueues[name]
+	if ok {
+		slice.Push(&queue.Pending, request)
+		log.Printf("PID: %d - Encolado en la interfaz %s - Cola: %v\n", pid, name, pendingPIDs(queue.Pending))
+	} else if members := poolMembers(name); len(members) > 0 {
+		pool, ok := globals.IOPools[name]
+		if !ok {
+			pool = &globals.T_IOPool{}
+			globals.IOPools[name] = pool
+		}
+		slice.Push(&pool.Pending, request)
+		log.Printf("PID: %d - Encolado en el pool %s - Cola: %v\n", pid, name, pendingPIDs(pool.Pending))
+		// Lo atiende un miembro libre; si están todos ocupados, el primero que se libere (ver takeNextIfFree)
+		queue = leastServedFree(members)
+	} else {
+		globals.IOMutex.Unlock()
+		fmt.Printf("La interfaz %s no está registrada\n", name)
+		TerminateBlocked(pid, pcb.ReasonIODisconnected)
+		return
 	}
 
-	err := json.NewDecoder(r.Body).Decode(&received_data)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	var next globals.T_IORequest
+	send := false
+	if queue != nil {
+		next, send = takeNextIfFree(queue)
 	}
+	globals.IOMutex.Unlock()
 
-	genericInterfaceBody = received_data
+	if send {
+		sendIORequest(queue.Interface, next)
+	}
+}
 
-	w.WriteHeader(http.StatusOK)
+// poolMembers: Colas de las interfaces del pool, en el orden en que se registraron. Requiere IOMutex tomado.
+func poolMembers(pool string) []*globals.T_IOQueue {
+	members := []*globals.T_IOQueue{}
+	for _, interf := range globals.Interfaces {
+		if interf.InterfacePool != pool {
+			continue
+		}
+		if member, ok := globals.IOQueues[interf.InterfaceName]; ok {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// leastServedFree: Miembro libre que menos solicitudes atendió (ante empate, el primero registrado), o nil si están todos ocupados
+func leastServedFree(members []*globals.T_IOQueue) *globals.T_IOQueue {
+	var chosen *globals.T_IOQueue
+	for _, member := range members {
+		if member.Load() > 0 {
+			continue
+		}
+		if chosen == nil || member.Served < chosen.Served {
+			chosen = member
+		}
+	}
+	return chosen
 }
 
 /**
- * RecvData_stdin: Recibe desde CPU la información necesaria para solicitar un STDIN_READ.
+ * ReleaseInterface: Marca como libre la interfaz que estaba atendiendo a un proceso y le envía la siguiente solicitud.
 
- * @param w: http.ResponseWriter -> Respuesta a enviar.
- * @param r: http.Request -> Request recibido.
+ * @param pid: uint32 -> PID del proceso que terminó de usar la interfaz.
+*/
+func ReleaseInterface(pid uint32) {
+	freeInterface(pid, true)
+}
+
+/**
+ * freeInterface: Libera la interfaz que estaba atendiendo a un proceso y le envía la siguiente solicitud.
+
+ * @param pid: uint32 -> PID del proceso que ocupaba la interfaz.
+ * @param served: bool -> Si la solicitud se completó (cuenta como atendida).
 */
+func freeInterface(pid uint32, served bool) {
+	var next globals.T_IORequest
+	var inter device.T_IOInterface
+	send := false
+
+	globals.IOMutex.Lock()
+	for _, queue := range globals.IOQueues {
+		if queue.BusyPID == pid {
+			queue.BusyPID = 0
+			queue.BusyTime += clock.Since(queue.BusySince)
+			if served {
+				queue.Served++
+			}
+			next, send = takeNextIfFree(queue)
+			inter = queue.Interface
+			break
+		}
+	}
+	globals.IOMutex.Unlock()
 
-func RecvData_stdin(w http.ResponseWriter, r *http.Request) {
-	var received_data struct {
-		DireccionesFisicas []globals.DireccionTamanio
-		InterfaceName      string
-		Tamanio            int
+	if send {
+		sendIORequest(inter, next)
 	}
+}
 
-	err := json.NewDecoder(r.Body).Decode(&received_data)
+/**
+ * DropIORequests: Descarta las solicitudes pendientes de un proceso (por ejemplo, al finalizarlo).
+
+ * @param pid: uint32 -> PID del proceso.
+*/
+func DropIORequests(pid uint32) {
+	globals.IOMutex.Lock()
+	defer globals.IOMutex.Unlock()
+
+	for _, queue := range globals.IOQueues {
+		dropPending(&queue.Pending, pid)
+	}
+	for _, pool := range globals.IOPools {
+		dropPending(&pool.Pending, pid)
+	}
+}
+
+func dropPending(pending *[]globals.T_IORequest, pid uint32) {
+	for i := len(*pending) - 1; i >= 0; i-- {
+		if (*pending)[i].PID == pid {
+			slice.RemoveAtIndex(pending, i)
+		}
+	}
+}
+
+// takeNextIfFree: Si la interfaz está libre, saca la próxima solicitud y la marca como ocupada. Primero atiende las dirigidas
+// a ella y después las de su pool, así el pool no espera a un miembro en particular. Requiere IOMutex tomado.
+func takeNextIfFree(queue *globals.T_IOQueue) (globals.T_IORequest, bool) {
+	if queue.BusyPID != 0 {
+		return globals.T_IORequest{}, false
+	}
+
+	var next globals.T_IORequest
+	pool, inPool := globals.IOPools[queue.Interface.InterfacePool]
+	switch {
+	case len(queue.Pending) > 0:
+		next = slice.Shift(&queue.Pending)
+	case queue.Interface.InterfacePool != "" && inPool && len(pool.Pending) > 0:
+		next = slice.Shift(&pool.Pending)
+	default:
+		return globals.T_IORequest{}, false
+	}
+
+	queue.BusyPID = next.PID
+	queue.BusySince = clock.Now()
+	return next, true
+}
+
+// sendIORequest: Envía una solicitud a la interfaz. Si la interfaz no la acepta, el proceso nunca recibiría respuesta:
+// se libera la interfaz para la siguiente solicitud y se finaliza el proceso.
+func sendIORequest(inter device.T_IOInterface, request globals.T_IORequest) {
+	url := fmt.Sprintf("http://%s:%d/io-operate", inter.InterfaceIP, inter.InterfacePort)
+
+	body, err := json.Marshal(request.Body(inter))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		log.Printf("PID: %d - No se pudo armar la solicitud para la interfaz %s: %v\n", request.PID, inter.InterfaceName, err)
+		abortIORequest(request.PID)
+		return
+	}
+
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		log.Printf("PID: %d - No se pudo enviar la solicitud a la interfaz %s: %v\n", request.PID, inter.InterfaceName, err)
+		abortIORequest(request.PID)
 		return
 	}
+	defer resp.Body.Close()
 
-	fmt.Println("Received data: ", received_data)
-	genericInterfaceBody = received_data
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("PID: %d - La interfaz %s rechazó la solicitud: %s\n", request.PID, inter.InterfaceName, resp.Status)
+		abortIORequest(request.PID)
+	}
+}
 
-	w.WriteHeader(http.StatusOK)
+// abortIORequest: Libera la interfaz que no aceptó la solicitud de un proceso y finaliza al proceso, que quedó bloqueado esperándola
+func abortIORequest(pid uint32) {
+	TerminateBlocked(pid, pcb.ReasonIODisconnected)
+	freeInterface(pid, false)
+}
+
+func pendingPIDs(pending []globals.T_IORequest) []uint32 {
+	pids := []uint32{}
+	for _, request := range pending {
+		pids = append(pids, request.PID)
+	}
+	return pids
+}
+
+type IOStatus_BRS struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Pool        string   `json:"pool,omitempty"`
+	BusyPID     uint32   `json:"busy_pid"`
+	Queued      []uint32 `json:"queued"`
+	Served      int      `json:"served"`
+	Utilization float64  `json:"utilization"`
 }
 
 /**
- * RecvData_stdout: Recibe desde CPU la información necesaria para solicitar un STDOUT_WRITE.
+ * IOStatus: Lista las interfaces registradas con el proceso que están atendiendo, los procesos encolados y su utilización.
 
  * @param w: http.ResponseWriter -> Respuesta a enviar.
  * @param r: *http.Request -> Request recibido.
 */
-func RecvData_stdout(w http.ResponseWriter, r *http.Request) {
-	var received_data struct {
-		DireccionesFisicas []globals.DireccionTamanio
-		InterfaceName      string
-	}
-
-	err := json.NewDecoder(r.Body).Decode(&received_data)
+func IOStatus(w http.ResponseWriter, r *http.Request) {
+	globals.IOMutex.Lock()
+	respBody := []IOStatus_BRS{}
+	for name, queue := range globals.IOQueues {
+		respBody = append(respBody, IOStatus_BRS{
+			Name:        name,
+			Type:        queue.Interface.InterfaceType,
+			Pool:        queue.Interface.InterfacePool,
+			BusyPID:     queue.BusyPID,
+			Queued:      pendingPIDs(queue.Pending),
+			Served:      queue.Served,
+			Utilization: queue.Utilization(),
+		})
+	}
+	// Las solicitudes de un pool esperan al primer miembro que se libere
+	for name, pool := range globals.IOPools {
+		respBody = append(respBody, IOStatus_BRS{
+			Name:   name,
+			Type:   "POOL",
+			Queued: pendingPIDs(pool.Pending),
+		})
+	}
+	globals.IOMutex.Unlock()
+
+	sort.Slice(respBody, func(i, j int) bool { return respBody[i].Name < respBody[j].Name })
+
+	response, err := json.Marshal(respBody)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	genericInterfaceBody = received_data
-
-	w.WriteHeader(http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(response)
 }
 
-/*
-	 RecvData_dialfs: Recibe desde CPU la información necesaria para solicitar un DIAL_FS.
+// ----------------------------- DESCONEXIÓN DE I/O -----------------------------
 
-	 @params:
-		- w: http.ResponseWriter -> Respuesta a enviar.
-		- r: *http.Request -> Request recibido.
+/**
+ * IOHeartbeat: Consulta periódicamente a cada interfaz registrada. La que no responde la cantidad de veces configurada se da de baja.
 */
+func IOHeartbeat() {
+	if globals.Configkernel.Io_heartbeat_interval <= 0 {
+		return
+	}
 
-func RecvData_dialfs(w http.ResponseWriter, r *http.Request) {
-	var received_data struct {
-		//	Pcb 					pcb.T_PCB
-		InterfaceName string
-		FileName      string
-		Size          int
-		Pointer       int
-		Address       []globals.DireccionTamanio
-		Operation     string
+	interval := time.Duration(globals.Configkernel.Io_heartbeat_interval) * time.Millisecond
+	for {
+		time.Sleep(interval)
+
+		globals.IOMutex.Lock()
+		interfaces := make([]device.T_IOInterface, len(globals.Interfaces))
+		copy(interfaces, globals.Interfaces)
+		globals.IOMutex.Unlock()
+
+		for _, interf := range interfaces {
+			alive := interfaceIsHealthy(interf)
+
+			globals.IOMutex.Lock()
+			queue, ok := globals.IOQueues[interf.InterfaceName]
+			if !ok {
+				globals.IOMutex.Unlock()
+				continue
+			}
+			if alive {
+				queue.MissedHeartbeats = 0
+			} else {
+				queue.MissedHeartbeats++
+				fmt.Printf("La interfaz %s no respondió el heartbeat (%d)\n", interf.InterfaceName, queue.MissedHeartbeats)
+			}
+			disconnected := queue.MissedHeartbeats >= max(globals.Configkernel.Io_heartbeat_misses, 1)
+			globals.IOMutex.Unlock()
+
+			if disconnected {
+				DeregisterInterface(interf.InterfaceName)
+			}
+		}
 	}
+}
 
-	err := json.NewDecoder(r.Body).Decode(&received_data)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+/**
+ * DisconnectIOInterface: Da de baja una interfaz que se desconecta de forma ordenada.
+
+ * @param w: http.ResponseWriter -> Respuesta a enviar.
+ * @param r: *http.Request -> Request recibido.
+*/
+func DisconnectIOInterface(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	if !DeregisterInterface(name) {
+		http.Error(w, "Device not found", http.StatusNotFound)
 		return
 	}
 
-	genericInterfaceBody = received_data
-
 	w.WriteHeader(http.StatusOK)
 }
 
-/*
-	RecvPCB_IO: Recibe el PCB bloqueado por IO, lo desbloquea y lo agrega a la cola de STS.
-
-	@params:
-		- w: http.ResponseWriter -> Respuesta a enviar.
-		- r: *http.Request -> Request recibido.
+/**
+ * DeregisterInterface: Quita una interfaz del sistema y finaliza con IO_DISCONNECTED a los procesos que la estaban usando o esperando.
 
+ * @param name: string -> Nombre de la interfaz.
+ * @return bool -> true si la interfaz estaba registrada.
 */
-func RecvPCB_IO(w http.ResponseWriter, r *http.Request) {
-	var received_pcb pcb.T_PCB
+func DeregisterInterface(name string) bool {
+	globals.IOMutex.Lock()
+	queue, ok := globals.IOQueues[name]
+	if !ok {
+		globals.IOMutex.Unlock()
+		return false
+	}
+
+	delete(globals.IOQueues, name)
+	removeInterface(name)
+
+	affected := pendingPIDs(queue.Pending)
+	if queue.BusyPID != 0 {
+		affected = append([]uint32{queue.BusyPID}, affected...)
+	}
+	// Si era el último miembro de su pool, nadie va a atender lo que esperaba en el pool
+	if poolName := queue.Interface.InterfacePool; poolName != "" && len(poolMembers(poolName)) == 0 {
+		if pool, ok := globals.IOPools[poolName]; ok {
+			affected = append(affected, pendingPIDs(pool.Pending)...)
+			delete(globals.IOPools, poolName)
+		}
+	}
+	globals.IOMutex.Unlock()
 
-	err := json.NewDecoder(r.Body).Decode(&received_pcb)
-	if err != nil {
-		http.Error(w, "Failed to decode PCB", http.StatusBadRequest)
-		return
+	log.Printf("Se desconecta la interfaz %s - Procesos afectados: %v\n", name, affected)
+	for _, pid := range affected {
+		TerminateBlocked(pid, pcb.ReasonIODisconnected)
 	}
+	return true
+}
 
-	fmt.Println("PCB que nos manda IO (Kernel): PC: ", received_pcb.PC, "PID: ", received_pcb.PID)
+/**
+ * TerminateBlocked: Finaliza un proceso bloqueado, liberando su grado de multiprogramación.
 
-	fmt.Println("Blocked: ", globals.Blocked)
+ * @param pid: uint32 -> PID del proceso.
+ * @param reason: pcb.EvictionReason -> Motivo de finalización.
+*/
+func TerminateBlocked(pid uint32, reason pcb.EvictionReason) {
+	blockedJob := RemoveByID(pid)
+	if blockedJob.PID == 0 {
+		return
+	}
 
-	RemoveByID(received_pcb.PID)
-	globals.ChangeState(&received_pcb, "READY")
+	// Queda registrado en el PCB para que GET /process muestre por qué terminó
+	blockedJob.EvictionReason = reason
+	KillJob(blockedJob)
+	<-globals.MultiprogrammingCounter
+	log.Printf("Finaliza el proceso %d - Motivo: %s\n", pid, reason)
+}
 
-	if (received_pcb.Quantum != globals.Configkernel.Quantum) {
-		slice.Push(&globals.STS_Priority, received_pcb)
-	} else {
-		slice.Push(&globals.STS, received_pcb)
+// removeInterface: Quita una interfaz de la lista de interfaces registradas. Requiere IOMutex tomado.
+func removeInterface(name string) {
+	for i := len(globals.Interfaces) - 1; i >= 0; i-- {
+		if globals.Interfaces[i].InterfaceName == name {
+			slice.RemoveAtIndex(&globals.Interfaces, i)
+		}
 	}
+}
 
-	globals.STSCounter <- int(received_pcb.PID)
+// interfaceIsHealthy: Consulta el health check de una interfaz.
+func interfaceIsHealthy(interf device.T_IOInterface) bool {
+	client := &http.Client{
+		Timeout: time.Second,
+	}
 
+	url := fmt.Sprintf("http://%s:%d/health", interf.InterfaceIP, interf.InterfacePort)
+	resp, err := client.Get(url)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
 
-	w.WriteHeader(http.StatusOK)
+	return resp.StatusCode == http.StatusOK
 }
@@ -0,0 +1,114 @@
+package kernel_api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	resource "github.com/sisoputnfrba/tp-golang/kernel/resources"
+	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+)
+
+type Resource_BRQ struct {
+	Name      string `json:"name"`
+	Instances int    `json:"instances"`
+}
+
+/**
+ * ResourceCreate: Crea un recurso en tiempo de ejecución
+ */
+func ResourceCreate(w http.ResponseWriter, r *http.Request) {
+	var request Resource_BRQ
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if resource.Exists(request.Name) {
+		http.Error(w, "Resource already exists", http.StatusConflict)
+		return
+	}
+
+	err = resource.CreateResource(request.Name, request.Instances)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+/**
+ * ResourceResize: Cambia la cantidad de instancias de un recurso, desbloqueando procesos si crece
+ */
+func ResourceResize(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	var request Resource_BRQ
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !resource.Exists(name) {
+		http.Error(w, "Resource not found", http.StatusNotFound)
+		return
+	}
+
+	err = resource.ResizeResource(name, request.Instances)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+/**
+ * ResourceDelete: Elimina un recurso. Los procesos que lo estaban esperando finalizan con RESOURCE_DELETED
+ */
+func ResourceDelete(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	waiters, err := resource.DeleteResource(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	for _, waiter := range waiters {
+		TerminateBlocked(waiter.PID, pcb.ReasonResourceDeleted)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+/**
+ * ResourceInfo: Devuelve las instancias, los poseedores y los procesos en espera de un recurso
+ */
+func ResourceInfo(w http.ResponseWriter, r *http.Request) {
+	info, err := resource.Describe(r.PathValue("name"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+/**
+ * ResourceList: Devuelve la vista de todos los recursos
+ */
+func ResourceList(w http.ResponseWriter, r *http.Request) {
+	infos := []resource.T_ResourceInfo{}
+	for _, name := range resource.ResourceNames() {
+		if info, err := resource.Describe(name); err == nil {
+			infos = append(infos, info)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(infos)
+}
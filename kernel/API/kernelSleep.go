@@ -0,0 +1,67 @@
+package kernel_api
+
+import (
+	"log"
+	"time"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/globals"
+	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+	"github.com/sisoputnfrba/tp-golang/utils/slice"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+)
+
+/**
+ * SleepJob: Bloquea un proceso en la cola de espera temporizada hasta que venza su timer.
+ * Reemplaza a IO_GEN_SLEEP cuando no hay una interfaz GENERICA conectada.
+
+ * @param sleeper: proceso que ejecutó SLEEP
+*/
+func SleepJob(sleeper pcb.T_PCB) {
+	sleepTime := time.Duration(sleeper.SleepTime) * time.Millisecond
+
+	globals.SleepingMutex.Lock()
+	slice.Push(&globals.Sleeping, globals.T_SleepingJob{PID: sleeper.PID, WakeAt: clock.Now().Add(sleepTime)})
+	globals.SleepingMutex.Unlock()
+
+	clock.AfterFunc(sleepTime, func() {
+		wakeUp(sleeper.PID)
+	})
+}
+
+/**
+ * wakeUp: Saca a un proceso de la cola de espera temporizada y lo pasa a READY.
+ * Si el proceso fue finalizado mientras dormía, no hace nada.
+
+ * @param pid: PID del proceso a despertar
+*/
+func wakeUp(pid uint32) {
+	globals.SleepingMutex.Lock()
+	for i, sleeper := range globals.Sleeping {
+		if sleeper.PID == pid {
+			slice.RemoveAtIndex(&globals.Sleeping, i)
+			break
+		}
+	}
+	globals.SleepingMutex.Unlock()
+
+	woken := RemoveByID(pid)
+	if woken.PID == 0 {
+		return
+	}
+
+	log.Printf("PID: %d - Vence el timer de SLEEP\n", pid)
+	if globals.HoldIfPaused(&woken) {
+		return
+	}
+	if !globals.ChangeState(&woken, pcb.StateReady) {
+		return
+	}
+
+	if woken.RemainingQuantum > 0 {
+		slice.Push(&globals.STS_Priority, woken)
+	} else {
+		slice.Push(&globals.STS, woken)
+	}
+
+	globals.STSCounter <- int(woken.PID)
+}
@@ -3,15 +3,21 @@ package kernel_api
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sisoputnfrba/tp-golang/kernel/globals"
 	resource "github.com/sisoputnfrba/tp-golang/kernel/resources"
 	"github.com/sisoputnfrba/tp-golang/utils/pcb"
 	"github.com/sisoputnfrba/tp-golang/utils/slice"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 )
 
 // ! Verificar que no se genere ningún problema de dependencias con resources
@@ -22,8 +28,14 @@ import (
 */
 
 type ProcessStart_BRQ struct {
-	PID  uint32 `json:"pid"`
-	Path string `json:"path"`
+	PID             uint32 `json:"pid"`
+	Path            string `json:"path"`
+	Tickets         int    `json:"tickets"`
+	MaxCPUTime      uint64 `json:"max_cpu_ms"`
+	MaxInstructions uint64 `json:"max_instructions"`
+	Priority        int    `json:"priority"`
+	Quantum         uint32 `json:"quantum"`
+	Class           string `json:"class"`
 }
 
 type ProcessStart_BRS struct {
@@ -31,9 +43,10 @@ type ProcessStart_BRS struct {
 }
 
 type GetInstructions_BRQ struct {
-	Path string `json:"path"`
-	Pid  uint32 `json:"pid"`
-	Pc   uint32 `json:"pc"`
+	Path      string   `json:"path"`
+	Pid       uint32   `json:"pid"`
+	Pc        uint32   `json:"pc"`
+	Resources []string `json:"resources"` // memoria advierte si el script usa un recurso que no está acá
 }
 
 /**
@@ -47,6 +60,12 @@ func ProcessInit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	quantum, err := quantumFor(request.Quantum, request.Class)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	pathInst, err := json.Marshal(fmt.Sprintf(request.Path))
 	if err != nil {
 		http.Error(w, "Error al codificar los datos como JSON", http.StatusInternalServerError)
@@ -57,25 +76,32 @@ func ProcessInit(w http.ResponseWriter, r *http.Request) {
 	newPcb := &pcb.T_PCB{
 		PID:     request.PID, // ! ESTO NO ESTABA >:v
 		PC:      0,
-		Quantum: globals.Configkernel.Quantum,
+		Quantum: quantum,
 		CPU_reg: map[string]interface{}{
-			"AX":  uint8(0),
-			"BX":  uint8(0),
-			"CX":  uint8(0),
-			"DX":  uint8(0),
-			"EAX": uint32(0),
-			"EBX": uint32(0),
-			"ECX": uint32(0),
-			"EDX": uint32(0),
-			"SI":  uint32(0),
-			"DI":  uint32(0),
-			"PC":  uint32(0),
+			"AX":    uint8(0),
+			"BX":    uint8(0),
+			"CX":    uint8(0),
+			"DX":    uint8(0),
+			"EAX":   uint32(0),
+			"EBX":   uint32(0),
+			"ECX":   uint32(0),
+			"EDX":   uint32(0),
+			"SI":    uint32(0),
+			"DI":    uint32(0),
+			"PC":    uint32(0),
+			"FLAGS": uint8(0),
+			"SP":    uint32(0),
 		},
-		State:             "NEW",
-		EvictionReason:    "",
+		State:             pcb.StateNew,
+		EvictionReason:    pcb.ReasonNone,
 		Resources:         make(map[string]int), // * El valor por defecto es 0, tener en cuenta por las dudas a la hora de testear
 		RequestedResource: "",
 		Executions:        0,
+		Tickets:           ticketsFor(request.Tickets),
+		MaxCPUTime:        limitFor(request.MaxCPUTime, globals.Configkernel.Max_cpu_ms),
+		MaxInstructions:   limitFor(request.MaxInstructions, globals.Configkernel.Max_instructions),
+		Priority:          request.Priority,
+		SchedulingClass:   request.Class,
 	}
 
 	var respBody ProcessStart_BRS = ProcessStart_BRS{PID: newPcb.PID}
@@ -89,9 +115,10 @@ func ProcessInit(w http.ResponseWriter, r *http.Request) {
 	url := fmt.Sprintf("http://%s:%d/instrucciones", globals.Configkernel.IP_memory, globals.Configkernel.Port_memory)
 
 	bodyInst, err := json.Marshal(GetInstructions_BRQ{
-		Path: pathInstString,
-		Pid:  newPcb.PID,
-		Pc:   newPcb.PC,
+		Path:      pathInstString,
+		Pid:       newPcb.PID,
+		Pc:        newPcb.PC,
+		Resources: resource.ResourceNames(),
 	})
 	if err != nil {
 		return
@@ -105,8 +132,19 @@ func ProcessInit(w http.ResponseWriter, r *http.Request) {
 	cliente := &http.Client{}
 	requerirInstrucciones.Header.Set("Content-Type", "application/json")
 	recibirRespuestaInstrucciones, err := cliente.Do(requerirInstrucciones)
-	if err != nil || recibirRespuestaInstrucciones.StatusCode != http.StatusOK {
+	if err != nil {
 		fmt.Println("Error en CargarInstrucciones (memoria)", err)
+	} else if recibirRespuestaInstrucciones.StatusCode == http.StatusBadRequest {
+		// El script no pasó la validación de memoria (errores con número de línea): el proceso no se crea
+		motivo, _ := io.ReadAll(recibirRespuestaInstrucciones.Body)
+		log.Printf("No se crea el proceso %d: %s", newPcb.PID, strings.TrimSpace(string(motivo)))
+		http.Error(w, strings.TrimSpace(string(motivo)), http.StatusBadRequest)
+		return
+	} else if recibirRespuestaInstrucciones.StatusCode != http.StatusOK {
+		fmt.Println("Error en CargarInstrucciones (memoria)", recibirRespuestaInstrucciones.Status)
+	} else {
+		// Código nuevo para este PID: CPU no debe seguir usando lo que tenga cacheado
+		RequestInstructionCacheInvalidation(newPcb.PID)
 	}
 
 	// Si la lista está vacía, la desbloqueo
@@ -127,6 +165,59 @@ func ProcessInit(w http.ResponseWriter, r *http.Request) {
 	w.Write(response)
 }
 
+/**
+  - ticketsFor: Devuelve la cantidad de tickets de un proceso nuevo. Si no se especifica, usa la de la configuración (mínimo 1)
+
+  - @param requested: Tickets pedidos en el request
+  - @return int: Tickets asignados
+*/
+func ticketsFor(requested int) int {
+	if requested > 0 {
+		return requested
+	}
+	if globals.Configkernel.Default_tickets > 0 {
+		return globals.Configkernel.Default_tickets
+	}
+	return 1
+}
+
+/**
+  - quantumFor: Devuelve el quantum base de un proceso nuevo. Un quantum explícito tiene prioridad sobre la clase;
+    sin ninguno de los dos se usa el quantum de la configuración.
+
+  - @param requested: Quantum pedido en el request
+  - @param class: Clase de planificación pedida en el request
+  - @return uint32: Quantum asignado
+  - @return error: Si la clase no está configurada
+*/
+func quantumFor(requested uint32, class string) (uint32, error) {
+	if requested > 0 {
+		return requested, nil
+	}
+	if class != "" {
+		quantum, ok := globals.Configkernel.Scheduling_classes[class]
+		if !ok {
+			return 0, fmt.Errorf("la clase de planificación %s no existe", class)
+		}
+		return quantum, nil
+	}
+	return globals.Configkernel.Quantum, nil
+}
+
+/**
+  - limitFor: Devuelve el límite de un proceso nuevo. Si no se especifica, usa el de la configuración (0 = sin límite)
+
+  - @param requested: Límite pedido en el request
+  - @param configured: Límite por defecto de la configuración
+  - @return uint64: Límite asignado
+*/
+func limitFor(requested uint64, configured uint64) uint64 {
+	if requested > 0 {
+		return requested
+	}
+	return configured
+}
+
 /**
   - ProcessDelete: Elimina un proceso en base a un PID. Realiza las operaciones como si el proceso llegase a EXIT
 */
@@ -138,7 +229,7 @@ func ProcessDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Si el proceso está en ejecución, se envía una interrupción para desalojarlo con INTERRUPTED_BY_USER, de lo contrario se elimina directamente y se saca de la cola en la que se encuentre 
-	if (pid == globals.CurrentJob.PID && globals.CurrentJob.State == "EXEC") {
+	if (pid == globals.CurrentJob.PID && globals.CurrentJob.State == pcb.StateExec) {
 		SendInterrupt("DELETE", pid, -1)
 	} else {
 		DeleteByID(pid)
@@ -147,8 +238,151 @@ func ProcessDelete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+/**
+  - ProcessPause: Saca a un proceso de la planificación sin finalizarlo.
+    Si está en EXEC se lo desaloja con una interrupción, si está en READY se lo quita de la cola,
+    y si está bloqueado (o en NEW) queda retenido cuando pase a READY.
+*/
+func ProcessPause(w http.ResponseWriter, r *http.Request) {
+	pid, err := GetPIDFromString(r.PathValue("pid"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	process, _ := SearchByID(pid, getProcessList())
+	if process == nil || process.State == pcb.StateTerminated {
+		http.Error(w, "Process not found", http.StatusNotFound)
+		return
+	}
+
+	globals.PausedMutex.Lock()
+	globals.PauseRequested[pid] = true
+	globals.PausedMutex.Unlock()
+
+	if pid == globals.CurrentJob.PID && globals.CurrentJob.State == pcb.StateExec {
+		SendInterrupt("PAUSE", pid, -1)
+	} else if readyJob, ok := removeFromReady(pid); ok {
+		globals.HoldIfPaused(&readyJob)
+	}
+
+	log.Printf("PID: %d - Pausa solicitada\n", pid)
+	w.WriteHeader(http.StatusOK)
+}
+
+/**
+  - ProcessParked: CPU avisa que frenó al proceso en ejecución en un breakpoint.
+    Mientras esté frenado no corren su quantum ni su límite de CPU, y ese tiempo no se le cuenta.
+*/
+func ProcessParked(w http.ResponseWriter, r *http.Request) {
+	pid, ok := burstPID(w, r)
+	if !ok {
+		return
+	}
+
+	globals.ParkBurst()
+	log.Printf("PID: %d - Frenado en un breakpoint de CPU\n", pid)
+	w.WriteHeader(http.StatusOK)
+}
+
+/**
+  - ProcessUnparked: CPU avisa que reanudó al proceso frenado. Sus timers siguen con lo que les quedaba.
+*/
+func ProcessUnparked(w http.ResponseWriter, r *http.Request) {
+	pid, ok := burstPID(w, r)
+	if !ok {
+		return
+	}
+
+	globals.ResumeBurst()
+	log.Printf("PID: %d - Reanudado en CPU\n", pid)
+	w.WriteHeader(http.StatusOK)
+}
+
+// burstPID: PID del pedido, que tiene que ser el del proceso en CPU. Si no lo es, responde el error.
+func burstPID(w http.ResponseWriter, r *http.Request) (uint32, bool) {
+	pid, err := GetPIDFromString(r.PathValue("pid"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return 0, false
+	}
+
+	globals.CPUMutex.Lock()
+	onCPU := globals.OnCPU && globals.CurrentJob.PID == pid
+	globals.CPUMutex.Unlock()
+	if !onCPU {
+		http.Error(w, "Process is not on CPU", http.StatusConflict)
+		return 0, false
+	}
+	return pid, true
+}
+
+/**
+  - ProcessResume: Devuelve a READY un proceso pausado. Si todavía no había sido retenido, cancela la pausa pendiente.
+*/
+func ProcessResume(w http.ResponseWriter, r *http.Request) {
+	pid, err := GetPIDFromString(r.PathValue("pid"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	globals.PausedMutex.Lock()
+	wasRequested := globals.PauseRequested[pid]
+	delete(globals.PauseRequested, pid)
+
+	_, index := SearchByID(pid, globals.Paused)
+	if index == -1 {
+		globals.PausedMutex.Unlock()
+		if !wasRequested {
+			http.Error(w, "Process is not paused", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	resumed := slice.RemoveAtIndex(&globals.Paused, index)
+	if !globals.ChangeState(&resumed, pcb.StateReady) {
+		slice.InsertAtIndex(&globals.Paused, index, resumed)
+		globals.PausedMutex.Unlock()
+		http.Error(w, "Illegal state transition", http.StatusConflict)
+		return
+	}
+	globals.PausedMutex.Unlock()
+
+	globals.STSMutex.Lock()
+	slice.Push(&globals.STS, resumed)
+	globals.STSMutex.Unlock()
+	globals.STSCounter <- int(resumed.PID)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+/**
+  - removeFromReady: Quita un proceso de las colas de READY sin liberar su grado de multiprogramación
+
+  - @param pid: PID del proceso a quitar
+  - @return pcb.T_PCB: Proceso quitado
+  - @return bool: true si el proceso estaba en READY
+*/
+func removeFromReady(pid uint32) (pcb.T_PCB, bool) {
+	globals.STSMutex.Lock()
+	defer globals.STSMutex.Unlock()
+
+	for _, queue := range []*[]pcb.T_PCB{&globals.STS, &globals.STS_Priority} {
+		if _, index := SearchByID(pid, *queue); index != -1 {
+			removed := slice.RemoveAtIndex(queue, index)
+			<- globals.STSCounter
+			return removed, true
+		}
+	}
+	return pcb.T_PCB{}, false
+}
+
 type ProcessStatus_BRS struct {
-	State string `json:"state"`
+	State  pcb.State          `json:"state"`
+	Reason pcb.EvictionReason `json:"reason,omitempty"`
 }
 
 /**
@@ -169,7 +403,7 @@ func ProcessState(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := ProcessStatus_BRS{State: process.State}
+	result := ProcessStatus_BRS{State: process.State, Reason: terminationReason(*process)}
 
 	response, err := json.Marshal(result)
 	if err != nil {
@@ -205,11 +439,35 @@ func PlanificationStop(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-type ProcessList_BRS struct {
-	Pid   int    `json:"pid"`
+type PlanificationState_BRS struct {
 	State string `json:"state"`
 }
 
+/**
+ * PlanificationState: Devuelve el estado de la planificación (RUNNING, STOPPED o CPU_UNAVAILABLE)
+ */
+func PlanificationState(w http.ResponseWriter, r *http.Request) {
+	response, err := json.Marshal(PlanificationState_BRS{State: globals.PlanningState})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
+
+type ProcessList_BRS struct {
+	Pid           int                `json:"pid"`
+	State         pcb.State          `json:"state"`
+	Cause         string             `json:"cause,omitempty"`
+	Priority      int                `json:"priority"`
+	InheritedFrom uint32             `json:"inherited_from,omitempty"`
+	Quantum       uint32             `json:"quantum"`
+	Class         string             `json:"class,omitempty"`
+	Reason        pcb.EvictionReason `json:"reason,omitempty"`
+}
+
 /**
  * ProcessList: Devuelve una lista de procesos con su PID y estado
 */
@@ -219,7 +477,16 @@ func ProcessList(w http.ResponseWriter, r *http.Request) {
 	// Formateo los procesos para devolverlos
 	respBody := make([]ProcessList_BRS, len(allProcesses))
 	for i, process := range allProcesses {
-		respBody[i] = ProcessList_BRS{Pid: int(process.PID), State: process.State}
+		respBody[i] = ProcessList_BRS{
+			Pid:           int(process.PID),
+			State:         process.State,
+			Cause:         process.BlockedBy,
+			Priority:      process.EffectivePriority(),
+			InheritedFrom: process.PriorityInheritedFrom,
+			Quantum:       process.Quantum,
+			Class:         process.SchedulingClass,
+			Reason:        terminationReason(process),
+		}
 	}
 
 	response, err := json.Marshal(respBody)
@@ -232,6 +499,62 @@ func ProcessList(w http.ResponseWriter, r *http.Request) {
 	w.Write(response)
 }
 
+type ShareReport_BRS struct {
+	Pid         uint32    `json:"pid"`
+	State       pcb.State `json:"state"`
+	Tickets     int       `json:"tickets"`
+	TicketShare float64   `json:"ticket_share"`
+	CPUTime     uint64    `json:"cpu_time"`
+	CPUShare    float64   `json:"cpu_share"`
+}
+
+/**
+ * ShareReport: Compara el tiempo de CPU que recibió cada proceso contra la proporción de tickets que posee
+*/
+func ShareReport(w http.ResponseWriter, r *http.Request) {
+	allProcesses := getProcessList()
+
+	totalTickets := 0
+	var totalCPUTime uint64
+	for _, process := range allProcesses {
+		totalTickets += process.EffectiveTickets()
+		totalCPUTime += process.CPUTime
+	}
+
+	respBody := make([]ShareReport_BRS, len(allProcesses))
+	for i, process := range allProcesses {
+		respBody[i] = ShareReport_BRS{
+			Pid:     process.PID,
+			State:   process.State,
+			Tickets: process.EffectiveTickets(),
+			CPUTime: process.CPUTime,
+		}
+		if totalTickets > 0 {
+			respBody[i].TicketShare = float64(process.EffectiveTickets()) / float64(totalTickets)
+		}
+		if totalCPUTime > 0 {
+			respBody[i].CPUShare = float64(process.CPUTime) / float64(totalCPUTime)
+		}
+	}
+
+	response, err := json.Marshal(respBody)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
+
+// terminationReason: Motivo por el que finalizó un proceso ("" si todavía no terminó)
+func terminationReason(process pcb.T_PCB) pcb.EvictionReason {
+	if process.State != pcb.StateTerminated {
+		return pcb.ReasonNone
+	}
+	return process.EvictionReason
+}
+
 /**
   - getProcessList: Devuelve una lista de todos los procesos en el sistema (LTS, STS, Blocked, STS_Priority, CurrentJob)
 
@@ -243,6 +566,7 @@ func getProcessList() []pcb.T_PCB {
 	allProcesses = append(allProcesses, globals.STS...)
 	allProcesses = append(allProcesses, globals.STS_Priority...)
 	allProcesses = append(allProcesses, globals.Blocked...)
+	allProcesses = append(allProcesses, globals.Paused...)
 	allProcesses = append(allProcesses, globals.Terminated...)
 	if globals.CurrentJob.PID != 0 && pidIsNotOnList(globals.CurrentJob.PID, allProcesses){
 		allProcesses = append(allProcesses, globals.CurrentJob)
@@ -265,20 +589,55 @@ func pidIsNotOnList(pid uint32, list []pcb.T_PCB) bool {
   - @return error: Error en caso de que falle el envío
 */
 func PCB_Send() error {
+	// Desde acá los cambios al proceso quedan pendientes hasta que vuelva (o hasta que falle el envío)
+	globals.EnterCPU()
+	defer globals.LeaveCPU()
+	globals.StartBurst()
+	defer globals.EndBurst()
+
 	jsonData, err := json.Marshal(globals.CurrentJob)
 	if err != nil {
 		return fmt.Errorf("failed to encode PCB: %v", err)
 	}
 
+	// Una ráfaga puede durar lo que sea (FIFO, un proceso en un breakpoint): el pedido no tiene timeout.
+	// Solo se limita el tiempo para conectarse, así únicamente se reintenta cuando CPU no es alcanzable.
 	client := &http.Client{
-		Timeout: 0,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{Timeout: ConnectTimeout()}).DialContext,
+		},
+	}
+
+	// Si el proceso tiene límite de CPU, se lo interrumpe cuando agote lo que le queda. El timer se detiene cuando vuelve (EndBurst).
+	if job := globals.CurrentJob; job.MaxCPUTime > 0 && job.CPUTime < job.MaxCPUTime {
+		remaining := time.Duration(job.MaxCPUTime-job.CPUTime) * time.Millisecond
+		globals.AddBurstTimer(clock.AfterFunc(remaining, func() {
+			SendInterrupt("LIMIT", job.PID, job.Executions)
+		}))
 	}
 
 	// Send data
 	url := fmt.Sprintf("http://%s:%d/dispatch", globals.Configkernel.IP_cpu, globals.Configkernel.Port_cpu)
-	resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("POST request failed. Failed to send PCB: %v", err)
+	backoff := RetryBackoff()
+	var resp *http.Response
+	for attempt := 1; ; attempt++ {
+		resp, err = client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+		if err == nil {
+			break
+		}
+		// Si la conexión llegó a establecerse, CPU recibió el PCB: solo se corta si CPU se cayó o cerró la conexión en plena ráfaga.
+		// Reenviarlo lo ejecutaría dos veces, así que se interrumpe lo que haya quedado y vuelve a READY con el último estado que conoce kernel.
+		if !isDialError(err) {
+			SendInterrupt("QUANTUM", globals.CurrentJob.PID, globals.CurrentJob.Executions)
+			return fmt.Errorf("POST request failed. CPU did not return the PCB: %v", err)
+		}
+		if attempt > globals.Configkernel.Cpu_dispatch_retries {
+			return fmt.Errorf("POST request failed. Failed to send PCB after %d attempts: %v", attempt, err)
+		}
+
+		log.Printf("PID: %d - Falló el despacho a CPU (intento %d): %v\n", globals.CurrentJob.PID, attempt, err)
+		time.Sleep(backoff)
+		backoff *= 2
 	}
 
 	// Wait for response
@@ -293,12 +652,71 @@ func PCB_Send() error {
 	if err != nil {
 		return fmt.Errorf("failed to decode PCB response: %v", err)
 	}
+	globals.CurrentJob.Instructions += globals.CurrentJob.DispatchInstructions
+	globals.LeaveCPU()
+	resource.ForgetDeletedResources(&globals.CurrentJob)
 
 	globals.PcbReceived <- true
 
 	return nil
 }
 
+// Espera por defecto para conectarse con CPU, si no se configuró cpu_connect_timeout
+const defaultConnectTimeout = 5 * time.Second
+
+/**
+  - ConnectTimeout: Devuelve cuánto se espera a establecer la conexión con CPU al despachar. No limita la ráfaga.
+
+  - @return time.Duration: Tiempo máximo para conectarse
+*/
+func ConnectTimeout() time.Duration {
+	if globals.Configkernel.Cpu_connect_timeout <= 0 {
+		return defaultConnectTimeout
+	}
+	return time.Duration(globals.Configkernel.Cpu_connect_timeout) * time.Millisecond
+}
+
+/**
+  - isDialError: Indica si el error ocurrió al conectarse con CPU (por ejemplo, conexión rechazada), es decir, antes de que recibiera el pedido
+
+  - @param err: error devuelto por el cliente HTTP
+  - @return bool: true si el pedido no llegó a CPU
+*/
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+/**
+  - RetryBackoff: Devuelve la espera inicial entre reintentos de comunicación con CPU
+
+  - @return time.Duration: Espera entre reintentos
+*/
+func RetryBackoff() time.Duration {
+	backoff := time.Duration(globals.Configkernel.Cpu_retry_backoff) * time.Millisecond
+	return max(backoff, 100*time.Millisecond)
+}
+
+/**
+  - CPUIsHealthy: Consulta si CPU está levantada y respondiendo
+
+  - @return bool: true si CPU respondió al health check
+*/
+func CPUIsHealthy() bool {
+	client := &http.Client{
+		Timeout: time.Second,
+	}
+
+	url := fmt.Sprintf("http://%s:%d/health", globals.Configkernel.IP_cpu, globals.Configkernel.Port_cpu)
+	resp, err := client.Get(url)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
+
 /**
   - SearchByID: Busca un proceso en la lista de procesos en base a su PID
 
@@ -346,6 +764,7 @@ func RemoveByID(pid uint32) pcb.T_PCB {
 	_, ltsIndex := SearchByID(pid, globals.LTS)
 	_, stsIndex := SearchByID(pid, globals.STS)
 	_, blockedIndex := SearchByID(pid, globals.Blocked)
+	_, pausedIndex := SearchByID(pid, globals.Paused)
 
 	var removedPCB pcb.T_PCB
 
@@ -367,6 +786,11 @@ func RemoveByID(pid uint32) pcb.T_PCB {
 		globals.BlockedMutex.Lock()
 		defer globals.BlockedMutex.Unlock()
 		removedPCB = slice.RemoveAtIndex(&globals.Blocked, blockedIndex)
+	} else if pausedIndex != -1 {
+		globals.PausedMutex.Lock()
+		defer globals.PausedMutex.Unlock()
+		removedPCB = slice.RemoveAtIndex(&globals.Paused, pausedIndex)
+		<- globals.MultiprogrammingCounter
 	} else {
 		return pcb.T_PCB{PID: 0} 
 	}
@@ -374,18 +798,28 @@ func RemoveByID(pid uint32) pcb.T_PCB {
 	return removedPCB
 }
 
-func KillJob(pcb pcb.T_PCB) {
-	globals.ChangeState(&pcb, "TERMINATED")
-	if (resource.HasResources(pcb)) {
-		advancedDeleting(pcb)
+func KillJob(job pcb.T_PCB) {
+	if !globals.ChangeState(&job, pcb.StateTerminated) {
+		return
 	}
-	slice.Push(&globals.Terminated, pcb)
-	RequestMemoryRelease(pcb.PID)
-	fmt.Print("Se eliminó el proceso ", pcb.PID, " satisfactoriamente\n")
+	resource.ReturnTickets(&job)
+	globals.ForgetPause(job.PID)
+	DropIORequests(job.PID)
+	advancedDeleting(job)
+	slice.Push(&globals.Terminated, job)
+	RequestMemoryRelease(job.PID)
+	// Toda finalización de kernel (DELETE, límites, IO desconectada, recurso eliminado) pasa por acá, esté o no el proceso en CPU
+	RequestInstructionCacheInvalidation(job.PID)
+	fmt.Print("Se eliminó el proceso ", job.PID, " satisfactoriamente\n")
 }
 
+/**
+  - advancedDeleting: Libera las instancias que poseía un proceso finalizado y lo saca de las colas de los recursos que esperaba
+
+  - @param pcb: Proceso finalizado
+*/
 func advancedDeleting(pcb pcb.T_PCB) {
-	for _ , res := range globals.Configkernel.Resources {
+	for _, res := range resource.ResourceNames() {
 		if count, ok := pcb.Resources[res]; ok && count > 0 {
 			pcb.Resources[res] = 0
 			for range count {
@@ -408,6 +842,8 @@ func advancedDeleting(pcb pcb.T_PCB) {
 		if index != -1 {
 			globals.MapMutex.Lock()
 			globals.ResourceMap[res] = append(globals.ResourceMap[res][:index], globals.ResourceMap[res][index+1:]...)
+			resource.CancelWaitTimer(pcb.PID)
+			resource.RefreshInheritance()
 			globals.MapMutex.Unlock()
 		}
 	}
@@ -498,6 +934,31 @@ func RequestMemoryRelease(pid uint32) {
 	}
 }
 
+/**
+ * RequestInstructionCacheInvalidation: Pide a CPU que descarte las instrucciones cacheadas de un proceso.
+ * Si CPU no responde no pasa nada: la caché es solo una optimización.
+
+ * @param pid: PID del proceso
+*/
+func RequestInstructionCacheInvalidation(pid uint32) {
+	client := &http.Client{
+		Timeout: time.Second,
+	}
+
+	url := fmt.Sprintf("http://%s:%d/instruction-cache?pid=%d", globals.Configkernel.IP_cpu, globals.Configkernel.Port_cpu, pid)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Printf("No se pudo invalidar la caché de instrucciones del PID %d: %v", pid, err)
+		return
+	}
+	resp.Body.Close()
+}
+
 /**
  * GetPIDList: Devuelve una lista de PID de todos los procesos en el sistema
 
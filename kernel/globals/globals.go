@@ -2,10 +2,15 @@ package globals
 
 import (
 	"log"
+	"maps"
+	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/sisoputnfrba/tp-golang/utils/device"
 	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+	"github.com/sisoputnfrba/tp-golang/utils/slice"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 )
 
 // Global variables
@@ -16,10 +21,28 @@ var (
 	Blocked 					[]pcb.T_PCB
 	STS_Priority 				[]pcb.T_PCB
 	Terminated 					[]pcb.T_PCB
+	Paused 						[]pcb.T_PCB
+	Sleeping 					[]T_SleepingJob
+	PauseRequested 				= make(map[uint32]bool)
 	Interfaces 					[]device.T_IOInterface
+	IOQueues 					= make(map[string]*T_IOQueue)
+	IOPools 					= make(map[string]*T_IOPool)
 	ResourceMap					map[string][]pcb.T_PCB
 	Resource_instances  		map[string]int
+	Resource_total 				map[string]int
+	WaitTimers 					= make(map[uint32]*clock.Timer)	// Timers de WAIT_TIMEOUT por PID, protegidos por MapMutex
 	PlanningState				string
+	// * Planificación proporcional (LOTTERY / STRIDE)
+	Lottery 					*rand.Rand
+	GlobalPass 					uint64
+	// * Proceso en CPU: el PCB que devuelve CPU reemplaza a CurrentJob, así que lo que kernel le cambie mientras tanto queda pendiente
+	OnCPU 						bool
+	PendingUpdates 				[]func(*pcb.T_PCB)
+	// * Ráfaga en curso: sus timers (quantum, límite de CPU) se pausan mientras CPU tiene al proceso frenado en un breakpoint
+	BurstTimers 				[]*clock.Timer
+	BurstParkedAt 				time.Time
+	BurstParked 				time.Duration 	// tiempo frenado en la ráfaga, que no cuenta como tiempo de CPU
+	BurstIsParked 				bool
 )
 
 // Global semaphores
@@ -32,6 +55,11 @@ var (
 		BlockedMutex			sync.Mutex
 		MapMutex 				sync.Mutex
 		EnganiaPichangaMutex	sync.Mutex
+		PausedMutex 			sync.Mutex
+		SleepingMutex 			sync.Mutex
+		IOMutex 				sync.Mutex
+		CPUMutex 				sync.Mutex
+		BurstMutex 				sync.Mutex
 	// * Binarios
 		LTSPlanBinary  			= make (chan bool, 1)
 		STSPlanBinary  			= make (chan bool, 1)
@@ -47,6 +75,58 @@ var (
 
 var CurrentJob pcb.T_PCB
 
+// Cola de solicitudes de una interfaz de I/O. Kernel le envía una solicitud a la vez.
+type T_IOQueue struct {
+	Interface 					device.T_IOInterface
+	BusyPID 					uint32
+	Pending 					[]T_IORequest
+	MissedHeartbeats 			int
+	RegisteredAt 				time.Time
+	BusySince 					time.Time
+	BusyTime 					time.Duration
+	Served 						int
+}
+
+// Load: Solicitudes asignadas a la interfaz (la que está atendiendo más las encoladas)
+func (q *T_IOQueue) Load() int {
+	load := len(q.Pending)
+	if q.BusyPID != 0 {
+		load++
+	}
+	return load
+}
+
+// Utilization: Fracción del tiempo desde que se registró la interfaz en que estuvo atendiendo solicitudes
+func (q *T_IOQueue) Utilization() float64 {
+	busyTime := q.BusyTime
+	if q.BusyPID != 0 {
+		busyTime += clock.Since(q.BusySince)
+	}
+
+	elapsed := clock.Since(q.RegisteredAt)
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(busyTime) / float64(elapsed)
+}
+
+// Solicitudes dirigidas a un pool. Las atiende el primer miembro que se libere.
+type T_IOPool struct {
+	Pending 					[]T_IORequest
+}
+
+// Solicitud de I/O pendiente. El body de /io-operate se arma al enviarla, cuando ya se sabe qué interfaz la atiende.
+type T_IORequest struct {
+	PID 						uint32
+	Body 						func(device.T_IOInterface) any
+}
+
+// Proceso dormido por la syscall SLEEP, esperando que venza su timer
+type T_SleepingJob struct {
+	PID 						uint32 		`json:"pid"`
+	WakeAt 						time.Time 	`json:"wake_at"`
+}
+
 type T_ConfigKernel struct {
 	Port 						int 		`json:"port"`
 	IP_memory 					string 		`json:"ip_memory"`
@@ -55,25 +135,208 @@ type T_ConfigKernel struct {
 	Port_cpu 					int 		`json:"port_cpu"`
 	Planning_algorithm 			string 		`json:"planning_algorithm"`
 	Quantum 					uint32 		`json:"quantum"`
+	Scheduling_classes 			map[string]uint32 	`json:"scheduling_classes"`
 	Resources 					[]string 	`json:"resources"`
 	Resource_instances 			[]int 		`json:"resource_instances"`
 	Multiprogramming 			int 		`json:"multiprogramming"`
+	Default_tickets 			int 		`json:"default_tickets"`
+	Lottery_seed 				int64 		`json:"lottery_seed"`
+	Max_cpu_ms 					uint64 		`json:"max_cpu_ms"`
+	Max_instructions 			uint64 		`json:"max_instructions"`
+	Cpu_connect_timeout 		int 		`json:"cpu_connect_timeout"`
+	Cpu_dispatch_retries 		int 		`json:"cpu_dispatch_retries"`
+	Cpu_retry_backoff 			int 		`json:"cpu_retry_backoff"`
+	Io_heartbeat_interval 		int 		`json:"io_heartbeat_interval"`
+	Io_heartbeat_misses 		int 		`json:"io_heartbeat_misses"`
+	Virtual_clock 				bool 		`json:"virtual_clock"`
+	Clock_idle_ms 				int 		`json:"clock_idle_ms"`
 }
 
 var Configkernel *T_ConfigKernel
 
-func ChangeState(pcb *pcb.T_PCB, newState string) {
+/**
+ * ChangeState: Cambia el estado de un proceso si la transición está permitida. Las transiciones ilegales se rechazan y se loguean.
+
+ * @param process: proceso a modificar
+ * @param newState: estado al que pasa
+ * @return bool: true si se realizó la transición
+*/
+func ChangeState(process *pcb.T_PCB, newState pcb.State) bool {
 	ProcessesMutex.Lock()
 	defer ProcessesMutex.Unlock()
-	
-	prevState := pcb.State
-	pcb.State = newState
-	log.Printf("PID: %d - Estado anterior: %s - Estado actual: %s \n", pcb.PID, prevState, pcb.State)
+
+	prevState := process.State
+	if !prevState.CanTransitionTo(newState) {
+		log.Printf("PID: %d - Transición ilegal: %s -> %s \n", process.PID, prevState, newState)
+		return false
+	}
+
+	process.State = newState
+	if newState != pcb.StateBlocked {
+		process.BlockedBy = ""
+	}
+	log.Printf("PID: %d - Estado anterior: %s - Estado actual: %s \n", process.PID, prevState, process.State)
+	return true
+}
+
+/**
+ * HoldIfPaused: Si se pidió pausar el proceso, lo retiene en la lista de pausados en lugar de pasarlo a READY
+
+ * @param process: proceso que está por pasar a READY
+ * @return bool: true si el proceso quedó retenido
+*/
+func HoldIfPaused(process *pcb.T_PCB) bool {
+	PausedMutex.Lock()
+	defer PausedMutex.Unlock()
+
+	if !PauseRequested[process.PID] {
+		return false
+	}
+
+	if !ChangeState(process, pcb.StatePaused) {
+		return false
+	}
+	slice.Push(&Paused, *process)
+	log.Printf("PID: %d - Retenido por pausa\n", process.PID)
+	return true
+}
+
+/**
+ * ForgetPause: Descarta un pedido de pausa pendiente (por ejemplo, al finalizar el proceso)
+
+ * @param pid: PID del proceso
+*/
+func ForgetPause(pid uint32) {
+	PausedMutex.Lock()
+	defer PausedMutex.Unlock()
+	delete(PauseRequested, pid)
+}
+
+/**
+ * UpdateCurrentJob: Modifica el proceso en ejecución. Si está en CPU, el cambio se aplica cuando vuelva (ver LeaveCPU).
+
+ * @param update: modificación a aplicar
+*/
+func UpdateCurrentJob(update func(*pcb.T_PCB)) {
+	CPUMutex.Lock()
+	defer CPUMutex.Unlock()
+
+	if OnCPU {
+		PendingUpdates = append(PendingUpdates, update)
+		return
+	}
+	update(&CurrentJob)
 }
-		
+
+// EnterCPU: Marca que el PCB de CurrentJob se envía a CPU. Se llama antes de serializarlo.
+func EnterCPU() {
+	CPUMutex.Lock()
+	defer CPUMutex.Unlock()
+	OnCPU = true
+}
+
+// LeaveCPU: Marca que el proceso volvió de CPU (o que no se pudo despachar) y le aplica los cambios pendientes
+func LeaveCPU() {
+	CPUMutex.Lock()
+	defer CPUMutex.Unlock()
+
+	if !OnCPU {
+		return
+	}
+	for _, update := range PendingUpdates {
+		update(&CurrentJob)
+	}
+	PendingUpdates = nil
+	OnCPU = false
+}
+
+/**
+ * CurrentJobView: Devuelve una copia del proceso en ejecución con los cambios pendientes ya aplicados
+
+ * @return pcb.T_PCB: proceso en ejecución como va a quedar cuando vuelva de CPU
+*/
+func CurrentJobView() pcb.T_PCB {
+	CPUMutex.Lock()
+	defer CPUMutex.Unlock()
+
+	view := CurrentJob
+	if len(PendingUpdates) == 0 {
+		return view
+	}
+	view.Resources = maps.Clone(CurrentJob.Resources)
+	for _, update := range PendingUpdates {
+		update(&view)
+	}
+	return view
+}
+
+// StartBurst: Empieza a contar una ráfaga nueva, sin tiempo frenado. Los timers ya agregados (el quantum) se conservan.
+func StartBurst() {
+	BurstMutex.Lock()
+	defer BurstMutex.Unlock()
+	BurstParked = 0
+	BurstIsParked = false
+}
+
+// AddBurstTimer: Asocia un timer a la ráfaga en curso, para pausarlo con ella y detenerlo cuando termine
+func AddBurstTimer(timer *clock.Timer) {
+	BurstMutex.Lock()
+	defer BurstMutex.Unlock()
+	BurstTimers = append(BurstTimers, timer)
+	if BurstIsParked {
+		timer.Pause()
+	}
+}
+
+// ParkBurst: CPU frenó al proceso en un breakpoint. Los timers de la ráfaga dejan de correr.
+func ParkBurst() {
+	BurstMutex.Lock()
+	defer BurstMutex.Unlock()
+	if BurstIsParked {
+		return
+	}
+	BurstIsParked = true
+	BurstParkedAt = clock.Now()
+	for _, timer := range BurstTimers {
+		timer.Pause()
+	}
+}
+
+// ResumeBurst: CPU reanudó al proceso. Los timers siguen con lo que les quedaba.
+func ResumeBurst() {
+	BurstMutex.Lock()
+	defer BurstMutex.Unlock()
+	if !BurstIsParked {
+		return
+	}
+	BurstIsParked = false
+	BurstParked += clock.Since(BurstParkedAt)
+	for _, timer := range BurstTimers {
+		timer.Resume()
+	}
+}
+
+// EndBurst: Detiene los timers de la ráfaga que terminó (el proceso volvió o no se pudo despachar)
+func EndBurst() {
+	BurstMutex.Lock()
+	defer BurstMutex.Unlock()
+	if BurstIsParked {
+		BurstIsParked = false
+		BurstParked += clock.Since(BurstParkedAt)
+	}
+	for _, timer := range BurstTimers {
+		timer.Stop()
+	}
+	BurstTimers = nil
+}
+
+// ParkedTime: Tiempo que estuvo frenado el proceso en la última ráfaga
+func ParkedTime() time.Duration {
+	BurstMutex.Lock()
+	defer BurstMutex.Unlock()
+	return BurstParked
+}
+
 var BlockedJob_by_IO pcb.T_PCB
 
-type DireccionTamanio struct {
-	DireccionFisica 	int 
-	Tamanio         	int 
-}
\ No newline at end of file
+type DireccionTamanio = pcb.DireccionTamanio
\ No newline at end of file
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	kernel_api "github.com/sisoputnfrba/tp-golang/kernel/API"
 	"github.com/sisoputnfrba/tp-golang/kernel/globals"
@@ -11,8 +12,11 @@ import (
 	cfg "github.com/sisoputnfrba/tp-golang/utils/config"
 	logger "github.com/sisoputnfrba/tp-golang/utils/log"
 	"github.com/sisoputnfrba/tp-golang/utils/server-Functions"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 )
 
+var virtualClock *clock.T_VirtualClock
+
 func main() {
 	logger.ConfigurarLogger("kernel.log")
 	logger.LogfileCreate("kernel_debug.log")
@@ -31,6 +35,14 @@ func main() {
 	globals.MultiprogrammingCounter = make (chan int, globals.Configkernel.Multiprogramming)
 	globals.STSCounter = make (chan int, globals.Configkernel.Multiprogramming)
 	resources.InitResourceMap()
+	kernelutils.SeedLottery(globals.Configkernel.Lottery_seed)
+
+	// Reloj virtual: kernel lo hospeda y el resto de los módulos lo consultan con clock_url.
+	// Avanza cuando ningún módulo tiene actividades en curso; clock_idle_ms es el margen de tiempo real antes de hacerlo.
+	if globals.Configkernel.Virtual_clock {
+		virtualClock = clock.NewVirtualClock(time.Duration(globals.Configkernel.Clock_idle_ms) * time.Millisecond)
+		clock.UseVirtual(virtualClock)
+	}
 
 	globals.EmptiedList <- false
 	globals.LTSPlanBinary <- false
@@ -38,6 +50,7 @@ func main() {
 	globals.PlanningState = "STOPPED"
 
 	go ServerStart(globals.Configkernel.Port)
+	go kernel_api.IOHeartbeat()
 
 	// * Planificación
 	go kernelutils.LTS_Plan()
@@ -56,20 +69,34 @@ func ServerStart(port int) {
 	mux.HandleFunc("PUT /process",				kernel_api.ProcessInit)
 	mux.HandleFunc("GET /process/{pid}", 		kernel_api.ProcessState)
 	mux.HandleFunc("DELETE /process/{pid}",		kernel_api.ProcessDelete)
+	mux.HandleFunc("PUT /process/{pid}/pause",	kernel_api.ProcessPause)
+	mux.HandleFunc("PUT /process/{pid}/resume",	kernel_api.ProcessResume)
+	mux.HandleFunc("PUT /process/{pid}/breakpoint",	kernel_api.ProcessParked)
+	mux.HandleFunc("DELETE /process/{pid}/breakpoint",	kernel_api.ProcessUnparked)
 	// Planificación
+	mux.HandleFunc("GET /plani", 				kernel_api.PlanificationState)
 	mux.HandleFunc("PUT /plani", 				kernel_api.PlanificationStart)
 	mux.HandleFunc("DELETE /plani",				kernel_api.PlanificationStop)
+	mux.HandleFunc("GET /plani/share",			kernel_api.ShareReport)
 	// I/O
+	mux.HandleFunc("GET /io", 					kernel_api.IOStatus)
+	mux.HandleFunc("DELETE /io/{name}", 		kernel_api.DisconnectIOInterface)
 	mux.HandleFunc("POST /io-handshake", 		kernel_api.GetIOInterface)
 	mux.HandleFunc("POST /io-interface", 		kernel_api.ExisteInterfaz)
-	mux.HandleFunc("POST /iodata-gensleep",		kernel_api.RecvData_gensleep)
-	mux.HandleFunc("POST /iodata-stdin", 		kernel_api.RecvData_stdin)
-	mux.HandleFunc("POST /iodata-stdout", 		kernel_api.RecvData_stdout)
-	mux.HandleFunc("POST /iodata-dialfs", 		kernel_api.RecvData_dialfs)
 	mux.HandleFunc("POST /io-return-pcb", 		kernel_api.RecvPCB_IO)
 	// Recursos
-	mux.HandleFunc("GET /resource-info", 		resources.GETResourcesInstances)
-	mux.HandleFunc("GET /resourceblocked", 		resources.GETResourceBlockedJobs)
+	mux.HandleFunc("GET /resource", 			kernel_api.ResourceList)
+	mux.HandleFunc("POST /resource", 			kernel_api.ResourceCreate)
+	mux.HandleFunc("GET /resource/{name}", 		kernel_api.ResourceInfo)
+	mux.HandleFunc("PATCH /resource/{name}", 	kernel_api.ResourceResize)
+	mux.HandleFunc("DELETE /resource/{name}", 	kernel_api.ResourceDelete)
+	// Reloj virtual
+	if virtualClock != nil {
+		mux.HandleFunc("GET /clock", 			virtualClock.HandleNow)
+		mux.HandleFunc("POST /clock/sleep", 	virtualClock.HandleSleep)
+		mux.HandleFunc("POST /clock/busy", 		virtualClock.HandleBusy)
+		mux.HandleFunc("POST /clock/idle", 		virtualClock.HandleIdle)
+	}
 
 	fmt.Printf("Server listening on port %d\n", port)
 	err := http.ListenAndServe(":"+fmt.Sprintf("%v", port), mux)
@@ -1,14 +1,15 @@
 package resource
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
+	"sort"
+	"time"
 
 	"github.com/sisoputnfrba/tp-golang/kernel/globals"
 	"github.com/sisoputnfrba/tp-golang/utils/pcb"
 	"github.com/sisoputnfrba/tp-golang/utils/slice"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 )
 
 /**
@@ -17,10 +18,12 @@ import (
 func InitResourceMap() {
 	globals.ResourceMap = make(map[string][]pcb.T_PCB)
 	globals.Resource_instances = make(map[string]int)
+	globals.Resource_total = make(map[string]int)
 
 	for i, resource := range globals.Configkernel.Resources {
 		globals.ResourceMap[resource] = []pcb.T_PCB{}
 		globals.Resource_instances[resource] = globals.Configkernel.Resource_instances[i]
+		globals.Resource_total[resource] = globals.Configkernel.Resource_instances[i]
 	}
 }
 
@@ -33,6 +36,7 @@ func InitResourceMap() {
 func QueueProcess(resource string, pcb pcb.T_PCB) {
 	globals.ResourceMap[resource] = append(globals.ResourceMap[resource], pcb)
 	slice.Push(&globals.Blocked, pcb)
+	RefreshInheritance()
 }
 
 /**
@@ -46,9 +50,30 @@ func DequeueProcess(resource string) pcb.T_PCB {
 	globals.ResourceMap[resource] = globals.ResourceMap[resource][1:]
 
 	RemoveFromBlocked(uint32(pcb.PID))
+	RefreshInheritance()
 	return pcb
 }
 
+/**
+ * RemoveFromQueue: Saca a un proceso de la cola de bloqueo de un recurso, si está
+
+ * @param resource: recurso en cuya cola se busca
+ * @param pid: PID del proceso
+ * @return pcb: proceso removido
+ * @return bool: true si estaba en la cola
+*/
+func RemoveFromQueue(resource string, pid uint32) (pcb.T_PCB, bool) {
+	for i, waiter := range globals.ResourceMap[resource] {
+		if waiter.PID == pid {
+			globals.ResourceMap[resource] = append(globals.ResourceMap[resource][:i], globals.ResourceMap[resource][i+1:]...)
+			RemoveFromBlocked(pid)
+			RefreshInheritance()
+			return waiter, true
+		}
+	}
+	return pcb.T_PCB{}, false
+}
+
 /**
  * RemoveFromBlocked: Remueve un proceso de la cola de bloqueo
 
@@ -71,23 +96,169 @@ func RequestConsumption(resource string) {
 	globals.MapMutex.Lock()
 	defer globals.MapMutex.Unlock()
 	if IsAvailable(resource) {
-		globals.ChangeState(&globals.CurrentJob, "READY")
 		globals.Resource_instances[resource]--
 		globals.CurrentJob.Resources[resource]++
 		fmt.Print("Se consumio una instancia del recurso: ", resource, "\n")
 		globals.CurrentJob.RequestedResource = ""
-		slice.Push(&globals.STS, globals.CurrentJob)
-		globals.STSCounter <- 1
+		if !globals.HoldIfPaused(&globals.CurrentJob) && globals.ChangeState(&globals.CurrentJob, pcb.StateReady) {
+			slice.Push(&globals.STS, globals.CurrentJob)
+			globals.STSCounter <- 1
+		}
 	} else {
 		fmt.Print("No hay instancias del recurso solicitado\n")
-		globals.ChangeState(&globals.CurrentJob, "BLOCKED")
+		if !globals.ChangeState(&globals.CurrentJob, pcb.StateBlocked) {
+			return
+		}
 		globals.CurrentJob.PC--	// Se decrementa el PC para que no avance en la próxima ejecución
 		log.Print("PID: ", globals.CurrentJob.PID, " - Bloqueado por: ", resource, "\n")
 		fmt.Print("Entra el proceso PID: ", globals.CurrentJob.PID, " a la cola de bloqueo del recurso ", resource,  "\n")
+		LendTickets(resource, &globals.CurrentJob)
 		QueueProcess(resource, globals.CurrentJob)
 	}
 }
 
+/**
+ * RequestTimedConsumption: Solicita una instancia de un recurso esperando a lo sumo WaitTimeout milisegundos (WAIT_TIMEOUT).
+ * Si no hay instancias, el proceso se bloquea sin retroceder el PC: al desbloquearse ya recibe la instancia.
+ * El resultado (1 = obtenida, 0 = venció el tiempo) se escribe en el registro de estado del proceso.
+
+ * @param resource: recurso a consumir
+*/
+func RequestTimedConsumption(resource string) {
+	globals.MapMutex.Lock()
+	defer globals.MapMutex.Unlock()
+
+	if IsAvailable(resource) {
+		takeInstance(resource, &globals.CurrentJob)
+		if !globals.HoldIfPaused(&globals.CurrentJob) && globals.ChangeState(&globals.CurrentJob, pcb.StateReady) {
+			slice.Push(&globals.STS, globals.CurrentJob)
+			globals.STSCounter <- 1
+		}
+		return
+	}
+
+	if !globals.ChangeState(&globals.CurrentJob, pcb.StateBlocked) {
+		return
+	}
+	log.Printf("PID: %d - Bloqueado por: %s (hasta %d ms)\n", globals.CurrentJob.PID, resource, globals.CurrentJob.WaitTimeout)
+	LendTickets(resource, &globals.CurrentJob)
+	QueueProcess(resource, globals.CurrentJob)
+
+	pid := globals.CurrentJob.PID
+	globals.WaitTimers[pid] = clock.AfterFunc(time.Duration(globals.CurrentJob.WaitTimeout)*time.Millisecond, func() {
+		waitTimedOut(resource, pid)
+	})
+}
+
+/**
+ * TryConsumption: Intenta consumir una instancia de un recurso sin bloquearse nunca (TRY_WAIT).
+ * El resultado (1 = obtenida, 0 = no había instancias) se escribe en el registro de estado del proceso.
+
+ * @param resource: recurso a consumir
+*/
+func TryConsumption(resource string) {
+	globals.MapMutex.Lock()
+	defer globals.MapMutex.Unlock()
+
+	if IsAvailable(resource) {
+		takeInstance(resource, &globals.CurrentJob)
+	} else {
+		fmt.Print("No hay instancias del recurso solicitado, el proceso sigue sin bloquearse\n")
+		globals.CurrentJob.RequestedResource = ""
+		setWaitStatus(&globals.CurrentJob, 0)
+	}
+
+	if !globals.HoldIfPaused(&globals.CurrentJob) && globals.ChangeState(&globals.CurrentJob, pcb.StateReady) {
+		slice.Push(&globals.STS, globals.CurrentJob)
+		globals.STSCounter <- 1
+	}
+}
+
+/**
+ * CancelWaitTimer: Detiene el timer de WAIT_TIMEOUT de un proceso, si tiene uno. Se llama con MapMutex tomado.
+
+ * @param pid: PID del proceso
+ * @return bool: true si el proceso estaba esperando con timeout
+*/
+func CancelWaitTimer(pid uint32) bool {
+	timer, ok := globals.WaitTimers[pid]
+	if !ok {
+		return false
+	}
+	timer.Stop()
+	delete(globals.WaitTimers, pid)
+	return true
+}
+
+/**
+ * waitTimedOut: Vence el WAIT_TIMEOUT de un proceso. Lo saca de la cola del recurso y lo pasa a READY con estado 0.
+ * Si ya recibió la instancia o fue finalizado, no hace nada.
+
+ * @param resource: recurso que esperaba
+ * @param pid: PID del proceso
+*/
+func waitTimedOut(resource string, pid uint32) {
+	globals.MapMutex.Lock()
+	defer globals.MapMutex.Unlock()
+
+	if _, ok := globals.WaitTimers[pid]; !ok {
+		return
+	}
+	delete(globals.WaitTimers, pid)
+
+	waiter, ok := RemoveFromQueue(resource, pid)
+	if !ok {
+		return
+	}
+
+	log.Printf("PID: %d - Vence la espera del recurso: %s\n", pid, resource)
+	ReturnTickets(&waiter)
+	waiter.RequestedResource = ""
+	setWaitStatus(&waiter, 0)
+	if globals.HoldIfPaused(&waiter) {
+		return
+	}
+	if !globals.ChangeState(&waiter, pcb.StateReady) {
+		return
+	}
+	globals.STS = append(globals.STS, waiter)
+	globals.STSCounter <- 1
+}
+
+/**
+ * takeInstance: Asigna una instancia de un recurso a un proceso y escribe 1 en su registro de estado
+
+ * @param resource: recurso a consumir
+ * @param process: proceso que recibe la instancia
+*/
+func takeInstance(resource string, process *pcb.T_PCB) {
+	globals.Resource_instances[resource]--
+	process.Resources[resource]++
+	process.RequestedResource = ""
+	setWaitStatus(process, 1)
+	fmt.Print("Se consumio una instancia del recurso: ", resource, "\n")
+}
+
+/**
+ * setWaitStatus: Escribe el resultado de WAIT_TIMEOUT / TRY_WAIT en el registro de estado del proceso
+
+ * @param process: proceso
+ * @param status: 1 si obtuvo la instancia, 0 si no
+*/
+func setWaitStatus(process *pcb.T_PCB, status uint32) {
+	if process.StatusRegister == "" {
+		return
+	}
+
+	if pcb.TipoReg(process.StatusRegister) == "uint8" {
+		process.CPU_reg[process.StatusRegister] = uint8(status)
+	} else {
+		process.CPU_reg[process.StatusRegister] = status
+	}
+	process.StatusRegister = ""
+	process.WaitTimeout = 0
+}
+
 /**
  * ReleaseConsumption: Solicita la liberación de una instancia de un recurso
 
@@ -105,9 +276,16 @@ func ReleaseConsumption(resource string) {
 	globals.CurrentJob.Resources[resource]--
 	globals.Resource_instances[resource]++
 	fmt.Print("Se libero una instancia del recurso: ", resource, "\n")
-	slice.InsertAtIndex(&globals.STS, 0, globals.CurrentJob)
+	ready := !globals.HoldIfPaused(&globals.CurrentJob) && globals.ChangeState(&globals.CurrentJob, pcb.StateReady)
+	if ready {
+		slice.InsertAtIndex(&globals.STS, 0, globals.CurrentJob)
+	}
 	ReleaseJobIfBlocked(resource)
-	globals.STSCounter <- 1
+	// Si ya no posee el recurso, deja de heredar la prioridad de quienes lo esperan
+	RefreshInheritance()
+	if ready {
+		globals.STSCounter <- 1
+	}
 }
 
 /**
@@ -141,10 +319,20 @@ func IsAvailable(resource string) bool {
 */
 func ReleaseJobIfBlocked(resource string) {
 	if len(globals.ResourceMap[resource]) > 0 {
-		pcb := DequeueProcess(resource)
-		globals.ChangeState(&pcb, "READY")
-		globals.STS = append(globals.STS, pcb)
-		fmt.Print("Se desbloqueo el proceso PID: ", pcb.PID, " del recurso ", resource, "\n")
+		released := DequeueProcess(resource)
+		ReturnTickets(&released)
+		fmt.Print("Se desbloqueo el proceso PID: ", released.PID, " del recurso ", resource, "\n")
+		// Un proceso en WAIT_TIMEOUT no vuelve a ejecutar la instrucción, así que recibe la instancia ahora
+		if CancelWaitTimer(released.PID) {
+			takeInstance(resource, &released)
+		}
+		if globals.HoldIfPaused(&released) {
+			return
+		}
+		if !globals.ChangeState(&released, pcb.StateReady) {
+			return
+		}
+		globals.STS = append(globals.STS, released)
 		globals.STSCounter <- 1
 	}
 }
@@ -180,27 +368,340 @@ func HasResources(pcb pcb.T_PCB) bool {
 	return false
 }
 
+// --------------------- TICKETS ------------------------
 
-// --------------------- API ------------------------
+/**
+ * UsesTickets: Consulta si el algoritmo de planificación configurado reparte la CPU por tickets
+
+ * @return bool: true si el algoritmo es LOTTERY o STRIDE
+*/
+func UsesTickets() bool {
+	return globals.Configkernel.Planning_algorithm == "LOTTERY" || globals.Configkernel.Planning_algorithm == "STRIDE"
+}
 
 /**
- * GETResourcesInstances: Consulta la cantidad de instancias de cada recurso
+ * LendTickets: Transfiere los tickets de un proceso que se bloquea por un recurso a un proceso que lo posee,
+ * para que el poseedor reciba más CPU y lo libere antes
 
- * @param w: response writer
- * @param r: request
+ * @param resource: recurso por el que se bloquea el proceso
+ * @param waiter: proceso que se bloquea
 */
-func GETResourcesInstances(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(globals.Resource_instances)
+func LendTickets(resource string, waiter *pcb.T_PCB) {
+	if !UsesTickets() || waiter.Tickets == 0 {
+		return
+	}
+
+	holderPID := findHolder(resource, waiter.PID)
+	if holderPID == 0 {
+		return
+	}
+
+	updateAllCopies(holderPID, func(holder *pcb.T_PCB) {
+		holder.BorrowedTickets += waiter.Tickets
+	})
+	waiter.TicketsLentTo = holderPID
+	log.Printf("PID: %d - Presta %d tickets al PID: %d (recurso %s)", waiter.PID, waiter.Tickets, holderPID, resource)
 }
 
 /**
- * GETResourceBlockedJobs: Consulta los procesos bloqueados por recurso
+ * ReturnTickets: Devuelve los tickets que un proceso había prestado al poseedor de un recurso
 
- * @param w: response writer
- * @param r: request
+ * @param waiter: proceso que había prestado sus tickets
 */
-func GETResourceBlockedJobs(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(globals.ResourceMap)
-}
\ No newline at end of file
+func ReturnTickets(waiter *pcb.T_PCB) {
+	if waiter.TicketsLentTo == 0 {
+		return
+	}
+
+	updateAllCopies(waiter.TicketsLentTo, func(holder *pcb.T_PCB) {
+		holder.BorrowedTickets -= waiter.Tickets
+		if holder.BorrowedTickets < 0 {
+			holder.BorrowedTickets = 0
+		}
+	})
+	log.Printf("PID: %d - Recupera %d tickets del PID: %d", waiter.PID, waiter.Tickets, waiter.TicketsLentTo)
+	waiter.TicketsLentTo = 0
+}
+
+/**
+ * findHolder: Busca un proceso (distinto al que espera) que posea instancias de un recurso
+
+ * @param resource: recurso a consultar
+ * @param waiterPID: PID del proceso que espera el recurso
+ * @return uint32: PID del poseedor, 0 si no hay ninguno
+*/
+func findHolder(resource string, waiterPID uint32) uint32 {
+	lists := [][]pcb.T_PCB{globals.STS, globals.STS_Priority, globals.Blocked}
+	for _, list := range lists {
+		for _, process := range list {
+			if process.PID != waiterPID && process.Resources[resource] > 0 {
+				return process.PID
+			}
+		}
+	}
+	return 0
+}
+
+/**
+ * updateAllCopies: Aplica una modificación a todas las copias del PCB de un proceso.
+ * Un proceso bloqueado por un recurso está tanto en Blocked como en la cola del recurso, por eso se actualizan todas.
+ * Si el proceso está en CPU, la modificación se aplica cuando vuelva.
+
+ * @param pid: PID del proceso a modificar
+ * @param update: modificación a aplicar
+*/
+func updateAllCopies(pid uint32, update func(*pcb.T_PCB)) {
+	lists := [][]pcb.T_PCB{globals.LTS, globals.STS, globals.STS_Priority, globals.Blocked, globals.Paused}
+	for _, queue := range globals.ResourceMap {
+		lists = append(lists, queue)
+	}
+
+	// Los slices comparten el arreglo subyacente con las colas globales, así que se modifican en el lugar
+	for _, list := range lists {
+		for i := range list {
+			if list[i].PID == pid {
+				update(&list[i])
+			}
+		}
+	}
+
+	if globals.CurrentJob.PID == pid {
+		globals.UpdateCurrentJob(update)
+	}
+}
+
+// --------------------- PRIORIDADES ------------------------
+
+/**
+ * RefreshInheritance: Recalcula la prioridad heredada de cada proceso que posee recursos.
+ * Un poseedor hereda la mayor prioridad efectiva entre los procesos que esperan alguno de sus recursos;
+ * cuando ya nadie de mayor prioridad lo espera, vuelve a su prioridad propia. Se llama con MapMutex tomado.
+ * Si el poseedor está en CPU, la prioridad heredada se le aplica cuando vuelva del despacho.
+*/
+func RefreshInheritance() {
+	for _, process := range liveProcesses() {
+		inherited, from := 0, uint32(0)
+		for res, count := range process.Resources {
+			if count == 0 {
+				continue
+			}
+			for _, waiter := range globals.ResourceMap[res] {
+				if waiter.PID != process.PID && waiter.EffectivePriority() > inherited {
+					inherited, from = waiter.EffectivePriority(), waiter.PID
+				}
+			}
+		}
+
+		if inherited <= process.Priority {
+			inherited, from = 0, 0
+		}
+		if inherited == process.InheritedPriority && from == process.PriorityInheritedFrom {
+			continue
+		}
+
+		if from != 0 {
+			log.Printf("PID: %d - Hereda prioridad %d del PID: %d", process.PID, inherited, from)
+		} else {
+			log.Printf("PID: %d - Vuelve a su prioridad %d", process.PID, process.Priority)
+		}
+		updateAllCopies(process.PID, func(holder *pcb.T_PCB) {
+			holder.InheritedPriority = inherited
+			holder.PriorityInheritedFrom = from
+		})
+	}
+}
+
+// --------------------- ADMINISTRACIÓN ------------------------
+
+// Vista de un recurso: instancias, procesos que lo poseen y procesos que lo esperan
+type T_ResourceInfo struct {
+	Name 		string 			`json:"name"`
+	Total 		int 			`json:"total"`
+	Available 	int 			`json:"available"`
+	Holders 	map[uint32]int 	`json:"holders"`
+	Waiters 	[]uint32 		`json:"waiters"`
+}
+
+/**
+ * CreateResource: Crea un recurso en tiempo de ejecución
+
+ * @param resource: nombre del recurso
+ * @param instances: cantidad de instancias
+ * @return error: si el recurso ya existe o la cantidad es inválida
+*/
+func CreateResource(resource string, instances int) error {
+	if resource == "" || instances < 0 {
+		return fmt.Errorf("recurso o cantidad de instancias inválidos")
+	}
+
+	globals.MapMutex.Lock()
+	defer globals.MapMutex.Unlock()
+
+	if _, ok := globals.Resource_instances[resource]; ok {
+		return fmt.Errorf("el recurso %s ya existe", resource)
+	}
+
+	globals.ResourceMap[resource] = []pcb.T_PCB{}
+	globals.Resource_instances[resource] = instances
+	globals.Resource_total[resource] = instances
+	log.Printf("Se crea el recurso %s con %d instancias\n", resource, instances)
+	return nil
+}
+
+/**
+ * ResizeResource: Cambia la cantidad total de instancias de un recurso. Si crece, desbloquea a tantos procesos como instancias nuevas haya.
+ * Si decrece por debajo de lo que está tomado, las instancias disponibles quedan negativas hasta que se liberen.
+
+ * @param resource: nombre del recurso
+ * @param instances: nueva cantidad total de instancias
+ * @return error: si el recurso no existe o la cantidad es inválida
+*/
+func ResizeResource(resource string, instances int) error {
+	if instances < 0 {
+		return fmt.Errorf("cantidad de instancias inválida")
+	}
+
+	globals.MapMutex.Lock()
+	defer globals.MapMutex.Unlock()
+
+	if _, ok := globals.Resource_instances[resource]; !ok {
+		return fmt.Errorf("el recurso %s no existe", resource)
+	}
+
+	delta := instances - globals.Resource_total[resource]
+	globals.Resource_total[resource] = instances
+	globals.Resource_instances[resource] += delta
+	log.Printf("El recurso %s pasa a tener %d instancias (disponibles: %d)\n", resource, instances, globals.Resource_instances[resource])
+
+	// Los desbloqueados por WAIT vuelven a ejecutarlo y toman la instancia al volver a CPU; los de WAIT_TIMEOUT la toman ahora
+	for available := globals.Resource_instances[resource]; available > 0 && len(globals.ResourceMap[resource]) > 0; available-- {
+		ReleaseJobIfBlocked(resource)
+	}
+	return nil
+}
+
+/**
+ * DeleteResource: Elimina un recurso. Los procesos que lo poseían dejan de tenerlo y los que lo esperaban se devuelven para ser finalizados.
+
+ * @param resource: nombre del recurso
+ * @return []pcb.T_PCB: procesos que esperaban el recurso (ya fuera de la cola del recurso)
+ * @return error: si el recurso no existe
+*/
+func DeleteResource(resource string) ([]pcb.T_PCB, error) {
+	globals.MapMutex.Lock()
+	defer globals.MapMutex.Unlock()
+
+	if _, ok := globals.Resource_instances[resource]; !ok {
+		return nil, fmt.Errorf("el recurso %s no existe", resource)
+	}
+
+	waiters := globals.ResourceMap[resource]
+	for _, waiter := range waiters {
+		CancelWaitTimer(waiter.PID)
+	}
+	delete(globals.ResourceMap, resource)
+	delete(globals.Resource_instances, resource)
+	delete(globals.Resource_total, resource)
+
+	for _, process := range liveProcesses() {
+		if process.Resources[resource] > 0 {
+			updateAllCopies(process.PID, func(holder *pcb.T_PCB) {
+				delete(holder.Resources, resource)
+			})
+		}
+	}
+	// Los que esperaban ya no están en ninguna cola: los poseedores dejan de heredar su prioridad
+	RefreshInheritance()
+
+	log.Printf("Se elimina el recurso %s - Procesos en espera: %d\n", resource, len(waiters))
+	return waiters, nil
+}
+
+/**
+ * ForgetDeletedResources: Quita de un proceso los recursos que ya no existen.
+ * El PCB que devuelve CPU se decodifica sobre el de kernel mezclando los mapas, así que un recurso eliminado mientras el proceso ejecutaba vuelve a aparecer.
+
+ * @param process: proceso que volvió de CPU
+*/
+func ForgetDeletedResources(process *pcb.T_PCB) {
+	globals.MapMutex.Lock()
+	defer globals.MapMutex.Unlock()
+
+	for res := range process.Resources {
+		if _, ok := globals.Resource_instances[res]; !ok {
+			delete(process.Resources, res)
+			log.Printf("PID: %d - Pierde el recurso eliminado %s\n", process.PID, res)
+		}
+	}
+}
+
+/**
+ * Describe: Devuelve la vista de un recurso
+
+ * @param resource: nombre del recurso
+ * @return T_ResourceInfo: instancias, poseedores y procesos en espera
+ * @return error: si el recurso no existe
+*/
+func Describe(resource string) (T_ResourceInfo, error) {
+	globals.MapMutex.Lock()
+	defer globals.MapMutex.Unlock()
+
+	available, ok := globals.Resource_instances[resource]
+	if !ok {
+		return T_ResourceInfo{}, fmt.Errorf("el recurso %s no existe", resource)
+	}
+
+	info := T_ResourceInfo{
+		Name:      resource,
+		Total:     globals.Resource_total[resource],
+		Available: available,
+		Holders:   make(map[uint32]int),
+		Waiters:   []uint32{},
+	}
+
+	for _, process := range liveProcesses() {
+		if count := process.Resources[resource]; count > 0 {
+			info.Holders[process.PID] = count
+		}
+	}
+	for _, waiter := range globals.ResourceMap[resource] {
+		info.Waiters = append(info.Waiters, waiter.PID)
+	}
+	return info, nil
+}
+
+/**
+ * ResourceNames: Devuelve los nombres de los recursos existentes, ordenados
+
+ * @return []string: nombres de los recursos
+*/
+func ResourceNames() []string {
+	globals.MapMutex.Lock()
+	defer globals.MapMutex.Unlock()
+
+	names := make([]string, 0, len(globals.Resource_instances))
+	for name := range globals.Resource_instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// liveProcesses: Procesos no finalizados, sin repetir (un bloqueado por recurso también está en la cola del recurso).
+// El proceso en ejecución se incluye con los cambios que tiene pendientes de cuando vuelva de CPU.
+func liveProcesses() []pcb.T_PCB {
+	var processes []pcb.T_PCB
+	seen := make(map[uint32]bool)
+
+	lists := [][]pcb.T_PCB{globals.LTS, globals.STS, globals.STS_Priority, globals.Blocked, globals.Paused, {globals.CurrentJobView()}}
+	for _, list := range lists {
+		for _, process := range list {
+			if process.PID == 0 || seen[process.PID] || process.State == pcb.StateTerminated {
+				continue
+			}
+			seen[process.PID] = true
+			processes = append(processes, process)
+		}
+	}
+	return processes
+}
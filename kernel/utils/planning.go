@@ -3,6 +3,7 @@ package kernelutils
 import (
 	"fmt"
 	"log"
+	"math/rand"
 	"time"
 
 	kernel_api "github.com/sisoputnfrba/tp-golang/kernel/API"
@@ -10,6 +11,7 @@ import (
 	resource "github.com/sisoputnfrba/tp-golang/kernel/resources"
 	"github.com/sisoputnfrba/tp-golang/utils/pcb"
 	"github.com/sisoputnfrba/tp-golang/utils/slice"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 )
 
 func LTS_Plan() {
@@ -31,7 +33,15 @@ func LTS_Plan() {
 		globals.LTSMutex.Unlock()
 		if auxJob.PID != 0 {
 			globals.MultiprogrammingCounter <- int(auxJob.PID)
-			globals.ChangeState(&auxJob, "READY")
+			// Un proceso nuevo arranca con el pass global para no monopolizar la CPU
+			auxJob.Pass = globals.GlobalPass
+			if globals.HoldIfPaused(&auxJob) {
+				continue
+			}
+			if !globals.ChangeState(&auxJob, pcb.StateReady) {
+				<-globals.MultiprogrammingCounter
+				continue
+			}
 			slice.Push(&globals.STS, auxJob)
 			log.Printf("Cola Ready STS: %v", kernel_api.GetPIDList(globals.STS))
 			globals.STSCounter <- int(auxJob.PID)
@@ -79,6 +89,45 @@ func STS_Plan() {
 			VRR_Plan()
 		}
 
+	case "LOTTERY":
+		fmt.Println("LOTTERY algorithm")
+		for {
+			if globals.PlanningState == "STOPPED" {
+				globals.STSPlanBinary <- true
+				<- globals.STSPlanBinary
+				continue
+			}
+
+			<-globals.STSCounter
+			LOTTERY_Plan()
+		}
+
+	case "STRIDE":
+		fmt.Println("STRIDE algorithm")
+		for {
+			if globals.PlanningState == "STOPPED" {
+				globals.STSPlanBinary <- true
+				<- globals.STSPlanBinary
+				continue
+			}
+
+			<-globals.STSCounter
+			STRIDE_Plan()
+		}
+
+	case "PRIORITY":
+		fmt.Println("PRIORITY algorithm")
+		for {
+			if globals.PlanningState == "STOPPED" {
+				globals.STSPlanBinary <- true
+				<- globals.STSPlanBinary
+				continue
+			}
+
+			<-globals.STSCounter
+			PRIORITY_Plan()
+		}
+
 	default:
 		fmt.Println("Not a planning algorithm")
 	}
@@ -94,12 +143,16 @@ type T_Quantum struct {
 func FIFO_Plan() {
 	globals.CurrentJob = slice.Shift(&globals.STS)
 
-	globals.ChangeState(&globals.CurrentJob, "EXEC")
+	if !globals.ChangeState(&globals.CurrentJob, pcb.StateExec) {
+		return
+	}
 	globals.CurrentJob.Executions++
 
-	kernel_api.PCB_Send()
-
-	<-globals.PcbReceived
+	timeBefore := clock.Now()
+	if !dispatch() {
+		return
+	}
+	accountCPUTime(timeBefore)
 
 	EvictionManagement()
 }
@@ -108,14 +161,19 @@ func RR_Plan() {
 	globals.EnganiaPichangaMutex.Lock()
 	
 	globals.CurrentJob = slice.Shift(&globals.STS)
-	globals.ChangeState(&globals.CurrentJob, "EXEC")
+	if !globals.ChangeState(&globals.CurrentJob, pcb.StateExec) {
+		globals.EnganiaPichangaMutex.Unlock()
+		return
+	}
 	globals.CurrentJob.Executions++
 	globals.EnganiaPichangaMutex.Unlock()
 
-	go startTimer(globals.CurrentJob.Quantum)
-	kernel_api.PCB_Send()                                            
-
-	<-globals.PcbReceived
+	timeBefore := clock.Now()
+	startTimer(globals.CurrentJob.TimeSlice())
+	if !dispatch() {
+		return
+	}
+	accountCPUTime(timeBefore)
 
 	EvictionManagement()
 }
@@ -129,46 +187,303 @@ func VRR_Plan() {
         globals.CurrentJob = slice.Shift(&globals.STS)
     }
 
-    globals.ChangeState(&globals.CurrentJob, "EXEC")
+    if !globals.ChangeState(&globals.CurrentJob, pcb.StateExec) {
+        globals.EnganiaPichangaMutex.Unlock()
+        return
+    }
 	globals.CurrentJob.Executions++
     globals.EnganiaPichangaMutex.Unlock()
 
-    timeBefore := time.Now()
-    go startTimer(globals.CurrentJob.Quantum)
-
-    kernel_api.PCB_Send()
+    timeSlice := globals.CurrentJob.TimeSlice()
+    timeBefore := clock.Now()
+    startTimer(timeSlice)
 
-    <-globals.PcbReceived
+    if !dispatch() {
+        return
+    }
 
     // Calcular el tiempo que tomó la ejecución
-    timeAfter := time.Now()
-    diffTime := uint32(time.Duration(timeAfter.Sub(timeBefore)).Milliseconds())
+    diffTime := accountCPUTime(timeBefore)
 
-    if diffTime < globals.CurrentJob.Quantum {
-        globals.CurrentJob.Quantum = globals.CurrentJob.Quantum - diffTime
-		fmt.Print("Quantum restante: ", globals.CurrentJob.Quantum)
+    // Lo que no usó de la ráfaga lo conserva; si la agotó, la próxima vuelve a su quantum base
+    if diffTime < timeSlice {
+        globals.CurrentJob.RemainingQuantum = timeSlice - diffTime
+		log.Printf("PID: %d - Quantum restante: %d\n", globals.CurrentJob.PID, globals.CurrentJob.RemainingQuantum)
     } else {
-        globals.CurrentJob.Quantum = globals.Configkernel.Quantum
+        globals.CurrentJob.RemainingQuantum = 0
     }
 
     EvictionManagement()
 }
 
+// Constante a dividir por los tickets de un proceso para obtener su stride
+const StrideConstant uint64 = 1 << 20
+
+/**
+ * SeedLottery: Inicializa el generador de números aleatorios de LOTTERY.
+ * Con una semilla fija los sorteos son reproducibles; con 0 se usa la hora actual.
+
+ * @param seed: semilla a utilizar
+*/
+func SeedLottery(seed int64) {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	globals.Lottery = rand.New(rand.NewSource(seed))
+	log.Printf("Semilla de LOTTERY: %d", seed)
+}
+
+/**
+  - LOTTERY_Plan: Sortea el próximo proceso a ejecutar, con probabilidad proporcional a sus tickets
+*/
+func LOTTERY_Plan() {
+	globals.EnganiaPichangaMutex.Lock()
+	if len(globals.STS) == 0 {
+		globals.EnganiaPichangaMutex.Unlock()
+		return
+	}
+
+	winner := DrawLotteryWinner(globals.STS, globals.Lottery)
+	globals.CurrentJob = slice.RemoveAtIndex(&globals.STS, winner)
+	log.Printf("PID: %d - Gana el sorteo con %d tickets", globals.CurrentJob.PID, ticketsOf(globals.CurrentJob))
+
+	if !globals.ChangeState(&globals.CurrentJob, pcb.StateExec) {
+		globals.EnganiaPichangaMutex.Unlock()
+		return
+	}
+	globals.CurrentJob.Executions++
+	globals.EnganiaPichangaMutex.Unlock()
+
+	timeBefore := clock.Now()
+	startTimer(globals.CurrentJob.TimeSlice())
+	if !dispatch() {
+		return
+	}
+	accountCPUTime(timeBefore)
+
+	EvictionManagement()
+}
+
+/**
+  - STRIDE_Plan: Ejecuta el proceso con menor pass y lo avanza en proporción inversa a sus tickets
+*/
+func STRIDE_Plan() {
+	globals.EnganiaPichangaMutex.Lock()
+	if len(globals.STS) == 0 {
+		globals.EnganiaPichangaMutex.Unlock()
+		return
+	}
+
+	globals.CurrentJob = slice.RemoveAtIndex(&globals.STS, MinPassIndex(globals.STS))
+	globals.GlobalPass = globals.CurrentJob.Pass
+
+	if !globals.ChangeState(&globals.CurrentJob, pcb.StateExec) {
+		globals.EnganiaPichangaMutex.Unlock()
+		return
+	}
+	globals.CurrentJob.Executions++
+	globals.EnganiaPichangaMutex.Unlock()
+
+	timeBefore := clock.Now()
+	startTimer(globals.CurrentJob.TimeSlice())
+	if !dispatch() {
+		return
+	}
+	diffTime := accountCPUTime(timeBefore)
+
+	// El pass avanza un stride completo por quantum consumido (como mínimo una unidad)
+	advance := StrideOf(globals.CurrentJob)
+	if globals.CurrentJob.Quantum > 0 {
+		advance = advance * uint64(diffTime) / uint64(globals.CurrentJob.Quantum)
+	}
+	globals.CurrentJob.Pass += max(advance, 1)
+
+	EvictionManagement()
+}
+
+/**
+  - PRIORITY_Plan: Ejecuta el proceso de mayor prioridad efectiva (propia o heredada), con quantum como RR
+*/
+func PRIORITY_Plan() {
+	globals.EnganiaPichangaMutex.Lock()
+	if len(globals.STS) == 0 {
+		globals.EnganiaPichangaMutex.Unlock()
+		return
+	}
+
+	globals.CurrentJob = slice.RemoveAtIndex(&globals.STS, MaxPriorityIndex(globals.STS))
+	log.Printf("PID: %d - Elegido con prioridad %d", globals.CurrentJob.PID, globals.CurrentJob.EffectivePriority())
+
+	if !globals.ChangeState(&globals.CurrentJob, pcb.StateExec) {
+		globals.EnganiaPichangaMutex.Unlock()
+		return
+	}
+	globals.CurrentJob.Executions++
+	globals.EnganiaPichangaMutex.Unlock()
+
+	timeBefore := clock.Now()
+	startTimer(globals.CurrentJob.TimeSlice())
+	if !dispatch() {
+		return
+	}
+	accountCPUTime(timeBefore)
+
+	EvictionManagement()
+}
+
+/**
+ * MaxPriorityIndex: Busca el proceso con mayor prioridad efectiva (ante empate, el primero en la cola)
+
+ * @param jobs: procesos en la cola de listos
+ * @return int: índice del proceso con mayor prioridad
+*/
+func MaxPriorityIndex(jobs []pcb.T_PCB) int {
+	maxIndex := 0
+	for i, job := range jobs {
+		if job.EffectivePriority() > jobs[maxIndex].EffectivePriority() {
+			maxIndex = i
+		}
+	}
+	return maxIndex
+}
+
+/**
+ * DrawLotteryWinner: Sortea un proceso de la lista en base a sus tickets
+
+ * @param jobs: procesos que participan del sorteo
+ * @param r: generador de números aleatorios
+ * @return int: índice del proceso ganador
+*/
+func DrawLotteryWinner(jobs []pcb.T_PCB, r *rand.Rand) int {
+	total := 0
+	for _, job := range jobs {
+		total += ticketsOf(job)
+	}
+
+	winningTicket := r.Intn(total)
+	for i, job := range jobs {
+		winningTicket -= ticketsOf(job)
+		if winningTicket < 0 {
+			return i
+		}
+	}
+	return len(jobs) - 1
+}
+
+/**
+ * MinPassIndex: Busca el proceso con menor pass (ante empate, el primero en la cola)
+
+ * @param jobs: procesos en la cola de listos
+ * @return int: índice del proceso con menor pass
+*/
+func MinPassIndex(jobs []pcb.T_PCB) int {
+	minIndex := 0
+	for i, job := range jobs {
+		if job.Pass < jobs[minIndex].Pass {
+			minIndex = i
+		}
+	}
+	return minIndex
+}
+
+/**
+ * StrideOf: Calcula el stride de un proceso en base a sus tickets
+
+ * @param job: proceso
+ * @return uint64: stride del proceso
+*/
+func StrideOf(job pcb.T_PCB) uint64 {
+	return StrideConstant / uint64(ticketsOf(job))
+}
+
+// Un proceso siempre participa con al menos un ticket
+func ticketsOf(job pcb.T_PCB) int {
+	return max(job.EffectiveTickets(), 1)
+}
+
+/**
+ * dispatch: Envía el proceso en ejecución a CPU y espera a que vuelva.
+ * Si CPU no responde, devuelve el proceso al frente de READY y espera a que CPU vuelva a estar disponible.
+
+ * @return bool: true si el proceso volvió de CPU
+*/
+func dispatch() bool {
+	if err := kernel_api.PCB_Send(); err != nil {
+		log.Printf("PID: %d - No se pudo despachar a CPU: %v\n", globals.CurrentJob.PID, err)
+		requeueCurrentJob()
+		waitForCPU()
+		return false
+	}
+
+	<-globals.PcbReceived
+	return true
+}
+
+/**
+ * requeueCurrentJob: Devuelve el proceso que no se pudo despachar al frente de la cola de la que salió,
+ * reponiendo el contador de STS que consumió el planificador
+*/
+func requeueCurrentJob() {
+	globals.EnganiaPichangaMutex.Lock()
+	if !globals.ChangeState(&globals.CurrentJob, pcb.StateReady) {
+		globals.EnganiaPichangaMutex.Unlock()
+		return
+	}
+	// Con quantum sin usar (VRR) había salido de la cola de prioridad
+	if globals.CurrentJob.RemainingQuantum > 0 {
+		slice.InsertAtIndex(&globals.STS_Priority, 0, globals.CurrentJob)
+	} else {
+		slice.InsertAtIndex(&globals.STS, 0, globals.CurrentJob)
+	}
+	globals.EnganiaPichangaMutex.Unlock()
+
+	globals.STSCounter <- int(globals.CurrentJob.PID)
+}
+
+/**
+ * waitForCPU: Marca la planificación como CPU_UNAVAILABLE y consulta el estado de CPU hasta que responda
+*/
+func waitForCPU() {
+	globals.PlanningState = "CPU_UNAVAILABLE"
+	log.Println("CPU no disponible - se suspende el despacho de procesos")
+
+	for !kernel_api.CPUIsHealthy() {
+		time.Sleep(kernel_api.RetryBackoff())
+	}
+
+	// Si mientras tanto se detuvo la planificación, se respeta
+	if globals.PlanningState == "CPU_UNAVAILABLE" {
+		globals.PlanningState = "RUNNING"
+	}
+	log.Println("CPU disponible nuevamente - se reanuda el despacho de procesos")
+}
+
+/**
+ * accountCPUTime: Suma al proceso en ejecución el tiempo de CPU que recibió en este despacho
+
+ * @param timeBefore: momento en que se despachó el proceso
+ * @return uint32: milisegundos de CPU recibidos
+*/
+func accountCPUTime(timeBefore time.Time) uint32 {
+	// Lo que estuvo frenado en un breakpoint no es tiempo de CPU
+	diffTime := uint32(max(clock.Since(timeBefore)-globals.ParkedTime(), 0).Milliseconds())
+	globals.CurrentJob.CPUTime += uint64(diffTime)
+	return diffTime
+}
+
+/**
+ * startTimer: Programa la interrupción por fin de quantum del proceso que se va a despachar.
+ * El timer es parte de la ráfaga: se pausa mientras CPU frena al proceso en un breakpoint y se detiene cuando vuelve.
+*/
 func startTimer(quantum uint32) {
 	quantumTime := time.Duration(quantum) * time.Millisecond
 	fmt.Println("Quantum time: ", quantumTime)
 	auxPcb := globals.CurrentJob
 
-	timeBefore := time.Now()
-	
-	time.Sleep(quantumTime)
-	
-	timeAfter := time.Now()
-    diffTime := uint32(time.Duration(timeAfter.Sub(timeBefore)).Milliseconds())
-	fmt.Println("START TIMER - Difftime: ", diffTime)	
-
-	fmt.Println("Salió de mimir el PID", auxPcb.PID)
-	quantumInterrupt(auxPcb)
+	globals.AddBurstTimer(clock.AfterFunc(quantumTime, func() {
+		fmt.Println("Salió de mimir el PID", auxPcb.PID)
+		quantumInterrupt(auxPcb)
+	}))
 }
 
 func quantumInterrupt(pcb pcb.T_PCB) {
@@ -183,12 +498,20 @@ func quantumInterrupt(pcb pcb.T_PCB) {
 */
 func EvictionManagement() {
 	evictionReason := globals.CurrentJob.EvictionReason
-	globals.CurrentJob.EvictionReason = ""
+	globals.CurrentJob.EvictionReason = pcb.ReasonNone
+
+	// Superar el límite de CPU o de instrucciones termina al proceso, salvo que ya esté terminando
+	if globals.CurrentJob.LimitExceeded() && !evictionReason.Terminates() {
+		evictionReason = pcb.ReasonLimitExceeded
+	}
 
 	switch evictionReason {
-	case "BLOCKED_IO_GEN":
+	case pcb.ReasonIOGen:
 		globals.EnganiaPichangaMutex.Lock()
-		globals.ChangeState(&globals.CurrentJob, "BLOCKED")
+		if !globals.ChangeState(&globals.CurrentJob, pcb.StateBlocked) {
+			globals.EnganiaPichangaMutex.Unlock()
+			break
+		}
 		
 		pcbAux := globals.CurrentJob
 		slice.Push(&globals.Blocked, globals.CurrentJob)
@@ -197,9 +520,12 @@ func EvictionManagement() {
 			kernel_api.SolicitarGenSleep(pcbAux)
 		}()
 
-	case "BLOCKED_IO_STDIN":
+	case pcb.ReasonIOStdin:
 		globals.EnganiaPichangaMutex.Lock()
-		globals.ChangeState(&globals.CurrentJob, "BLOCKED")
+		if !globals.ChangeState(&globals.CurrentJob, pcb.StateBlocked) {
+			globals.EnganiaPichangaMutex.Unlock()
+			break
+		}
 		
 		pcbAux := globals.CurrentJob
 		slice.Push(&globals.Blocked, globals.CurrentJob)
@@ -208,9 +534,12 @@ func EvictionManagement() {
 			kernel_api.SolicitarStdinRead(pcbAux)
 		}()
 
-	case "BLOCKED_IO_STDOUT":
+	case pcb.ReasonIOStdout:
 		globals.EnganiaPichangaMutex.Lock()
-		globals.ChangeState(&globals.CurrentJob, "BLOCKED")
+		if !globals.ChangeState(&globals.CurrentJob, pcb.StateBlocked) {
+			globals.EnganiaPichangaMutex.Unlock()
+			break
+		}
 		
 		pcbAux := globals.CurrentJob
 		slice.Push(&globals.Blocked, globals.CurrentJob)
@@ -219,9 +548,12 @@ func EvictionManagement() {
 			kernel_api.SolicitarStdoutWrite(pcbAux)
 		}()
 
-	case "BLOCKED_IO_DIALFS":
+	case pcb.ReasonIODialFS:
 		globals.EnganiaPichangaMutex.Lock()
-		globals.ChangeState(&globals.CurrentJob, "BLOCKED")
+		if !globals.ChangeState(&globals.CurrentJob, pcb.StateBlocked) {
+			globals.EnganiaPichangaMutex.Unlock()
+			break
+		}
 
 		pcbAux := globals.CurrentJob
 		slice.Push(&globals.Blocked, globals.CurrentJob)
@@ -230,46 +562,116 @@ func EvictionManagement() {
 			kernel_api.SolicitarDialFS(pcbAux)
 		}()
 
-	case "TIMEOUT":
-		globals.ChangeState(&globals.CurrentJob, "READY")
+	case pcb.ReasonSleep:
+		if !globals.ChangeState(&globals.CurrentJob, pcb.StateBlocked) {
+			break
+		}
+		globals.CurrentJob.BlockedBy = "timer"
+
+		slice.Push(&globals.Blocked, globals.CurrentJob)
+		log.Printf("PID: %d - Bloqueado por SLEEP (%d ms)\n", globals.CurrentJob.PID, globals.CurrentJob.SleepTime)
+		kernel_api.SleepJob(globals.CurrentJob)
+
+	case pcb.ReasonTimeout, pcb.ReasonMemoryUnavailable:
+		if globals.HoldIfPaused(&globals.CurrentJob) {
+			break
+		}
+		if !globals.ChangeState(&globals.CurrentJob, pcb.StateReady) {
+			break
+		}
 		globals.STS = append(globals.STS, globals.CurrentJob)
-		log.Printf("PID: %d - Desalojado por fin de quantum\n", globals.CurrentJob.PID)
+		if evictionReason == pcb.ReasonTimeout {
+			log.Printf("PID: %d - Desalojado por fin de quantum\n", globals.CurrentJob.PID)
+		} else {
+			// CPU no pudo traer la instrucción: el proceso no avanzó y se vuelve a intentar más tarde
+			log.Printf("PID: %d - Desalojado porque memoria no responde\n", globals.CurrentJob.PID)
+		}
 		globals.STSCounter <- int(globals.CurrentJob.PID)
 
-	case "EXIT":
-		globals.ChangeState(&globals.CurrentJob, "TERMINATED")
+	case pcb.ReasonExit, pcb.ReasonDivisionByZero, pcb.ReasonStackOverflow, pcb.ReasonStackUnderflow:
+		if !globals.ChangeState(&globals.CurrentJob, pcb.StateTerminated) {
+			break
+		}
 		kernel_api.KillJob(globals.CurrentJob)
 		<-globals.MultiprogrammingCounter
 		log.Printf("Finaliza el proceso %d - Motivo: %s\n", globals.CurrentJob.PID, evictionReason)
 
-	case "WAIT":
+	case pcb.ReasonWait:
 		if resource.Exists(globals.CurrentJob.RequestedResource) {
 			resource.RequestConsumption(globals.CurrentJob.RequestedResource)
 
 		} else {
 			fmt.Print("El recurso no existe\n")
-			globals.CurrentJob.EvictionReason = "EXIT"
+			globals.CurrentJob.EvictionReason = pcb.ReasonExit
 			EvictionManagement()
 		}
 
-	case "SIGNAL":
+	case pcb.ReasonWaitTimeout:
+		if resource.Exists(globals.CurrentJob.RequestedResource) {
+			resource.RequestTimedConsumption(globals.CurrentJob.RequestedResource)
+
+		} else {
+			fmt.Print("El recurso no existe\n")
+			globals.CurrentJob.EvictionReason = pcb.ReasonExit
+			EvictionManagement()
+		}
+
+	case pcb.ReasonTryWait:
+		if resource.Exists(globals.CurrentJob.RequestedResource) {
+			resource.TryConsumption(globals.CurrentJob.RequestedResource)
+
+		} else {
+			fmt.Print("El recurso no existe\n")
+			globals.CurrentJob.EvictionReason = pcb.ReasonExit
+			EvictionManagement()
+		}
+
+	case pcb.ReasonSignal:
 		if resource.Exists(globals.CurrentJob.RequestedResource) {
 			resource.ReleaseConsumption(globals.CurrentJob.RequestedResource)
 
 		} else {
 			fmt.Print("El recurso no existe\n")
-			globals.CurrentJob.EvictionReason = "EXIT"
+			globals.CurrentJob.EvictionReason = pcb.ReasonExit
 			EvictionManagement()
 		}
 
-	case "OUT_OF_MEMORY":
-		globals.ChangeState(&globals.CurrentJob, "TERMINATED")
+	case pcb.ReasonInvalidInstruction, pcb.ReasonInvalidRegister:
+		if !globals.ChangeState(&globals.CurrentJob, pcb.StateTerminated) {
+			break
+		}
+		kernel_api.KillJob(globals.CurrentJob)
+		<-globals.MultiprogrammingCounter
+		log.Printf("Finaliza el proceso %d - Motivo: %s - Program Counter: %d - %s\n", globals.CurrentJob.PID, evictionReason, globals.CurrentJob.PC, globals.CurrentJob.Exception)
+
+	case pcb.ReasonOutOfMemory:
+		if !globals.ChangeState(&globals.CurrentJob, pcb.StateTerminated) {
+			break
+		}
 		kernel_api.KillJob(globals.CurrentJob)
 		<-globals.MultiprogrammingCounter
 		log.Printf("Finaliza el proceso %d - Motivo: %s\n", globals.CurrentJob.PID, evictionReason)
 
-	case "INTERRUPTED_BY_USER":
-		globals.ChangeState(&globals.CurrentJob, "TERMINATED")
+	case pcb.ReasonLimitExceeded:
+		if !globals.ChangeState(&globals.CurrentJob, pcb.StateTerminated) {
+			break
+		}
+		kernel_api.KillJob(globals.CurrentJob)
+		<-globals.MultiprogrammingCounter
+		log.Printf("Finaliza el proceso %d - Motivo: %s (CPU: %d ms, instrucciones: %d)\n", globals.CurrentJob.PID, evictionReason, globals.CurrentJob.CPUTime, globals.CurrentJob.Instructions)
+
+	case pcb.ReasonPaused:
+		log.Printf("PID: %d - Desalojado por pausa\n", globals.CurrentJob.PID)
+		// Si se reanudó antes de que llegue el desalojo, vuelve directamente a READY
+		if !globals.HoldIfPaused(&globals.CurrentJob) && globals.ChangeState(&globals.CurrentJob, pcb.StateReady) {
+			globals.STS = append(globals.STS, globals.CurrentJob)
+			globals.STSCounter <- int(globals.CurrentJob.PID)
+		}
+
+	case pcb.ReasonInterruptedByUser:
+		if !globals.ChangeState(&globals.CurrentJob, pcb.StateTerminated) {
+			break
+		}
 		kernel_api.KillJob(globals.CurrentJob)
 		<-globals.MultiprogrammingCounter
 		log.Printf("Finaliza el proceso %d - Motivo: %s\n", globals.CurrentJob.PID, evictionReason)
@@ -0,0 +1,88 @@
+package kernelutils
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/sisoputnfrba/tp-golang/kernel/globals"
+	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+)
+
+func lotteryJobs(tickets ...int) []pcb.T_PCB {
+	jobs := make([]pcb.T_PCB, len(tickets))
+	for i, t := range tickets {
+		jobs[i] = pcb.T_PCB{PID: uint32(i + 1), Tickets: t}
+	}
+	return jobs
+}
+
+func drawSequence(jobs []pcb.T_PCB, draws int) []int {
+	winners := make([]int, draws)
+	for i := range winners {
+		winners[i] = DrawLotteryWinner(jobs, globals.Lottery)
+	}
+	return winners
+}
+
+// Con la misma semilla, los sorteos se repiten
+func TestSeedLotteryIsReproducible(t *testing.T) {
+	jobs := lotteryJobs(10, 20, 30, 40)
+
+	SeedLottery(42)
+	first := drawSequence(jobs, 200)
+	SeedLottery(42)
+	second := drawSequence(jobs, 200)
+
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("sorteo %d: %d con la primera pasada, %d con la segunda", i, first[i], second[i])
+		}
+	}
+}
+
+// Con semilla 0 igual queda un generador listo para sortear
+func TestSeedLotteryZeroUsesClock(t *testing.T) {
+	globals.Lottery = nil
+	SeedLottery(0)
+	if globals.Lottery == nil {
+		t.Fatal("SeedLottery(0) no inicializó el generador")
+	}
+}
+
+// Cada proceso gana en proporción a sus tickets, contando los prestados
+func TestDrawLotteryWinnerIsProportional(t *testing.T) {
+	jobs := lotteryJobs(10, 30, 0)
+	jobs[2].BorrowedTickets = 60
+
+	const draws = 100000
+	wins := make([]int, len(jobs))
+	r := rand.New(rand.NewSource(1))
+	for range draws {
+		wins[DrawLotteryWinner(jobs, r)]++
+	}
+
+	for i, want := range []float64{0.1, 0.3, 0.6} {
+		got := float64(wins[i]) / draws
+		if math.Abs(got-want) > 0.01 {
+			t.Errorf("PID %d: ganó %.3f de los sorteos, se esperaba %.1f", jobs[i].PID, got, want)
+		}
+	}
+}
+
+// Un proceso sin tickets participa con uno
+func TestDrawLotteryWinnerWithoutTickets(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	if winner := DrawLotteryWinner(lotteryJobs(0), r); winner != 0 {
+		t.Fatalf("con un solo proceso ganó el índice %d", winner)
+	}
+
+	wins := make([]int, 2)
+	for range 1000 {
+		wins[DrawLotteryWinner(lotteryJobs(0, 0), r)]++
+	}
+	if wins[0] == 0 || wins[1] == 0 {
+		t.Errorf("dos procesos sin tickets deberían ganar alguna vez cada uno, ganaron %v", wins)
+	}
+}
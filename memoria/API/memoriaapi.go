@@ -13,12 +13,15 @@ import (
 	"time"
 
 	"github.com/sisoputnfrba/tp-golang/memoria/globals"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
+	"github.com/sisoputnfrba/tp-golang/utils/validator"
 )
 
 type GetInstructions_BRQ struct {
-	Path string `json:"path"`
-	Pid  uint32 `json:"pid"`
-	Pc   uint32 `json:"pc"`
+	Path      string   `json:"path"`
+	Pid       uint32   `json:"pid"`
+	Pc        uint32   `json:"pc"`
+	Resources []string `json:"resources,omitempty"` // recursos de kernel; si no vienen no se validan
 }
 
 type BitMap []int
@@ -43,7 +46,7 @@ func CargarInstrucciones(w http.ResponseWriter, r *http.Request) {
 	pid := request.Pid
 	pc := request.Pc
 
-	var instrucciones []string
+	var lineas []string
 	//Lee linea por linea el archivo
 	file := AbrirArchivo(globals.Configmemory.Instructions_path + pathInstrucciones)
 	defer file.Close()
@@ -51,15 +54,34 @@ func CargarInstrucciones(w http.ResponseWriter, r *http.Request) {
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		// Agregar cada línea al slice de strings
-		instrucciones = append(instrucciones, scanner.Text())
+		lineas = append(lineas, scanner.Text())
 	}
 	if err := scanner.Err(); err != nil {
 		log.Fatal(err)
 	}
 
+	// Se valida el script completo y se resuelven las etiquetas; si tiene errores, el proceso no se crea (las advertencias solo se loguean)
+	programa, errores := validator.Validate(lineas, validator.T_Context{Resources: request.Resources})
+	if validator.HasErrors(errores) {
+		mensajes := make([]string, 0, len(errores))
+		for _, e := range errores {
+			log.Printf("PID: %d - Error en %s: %s", pid, pathInstrucciones, e)
+			if e.Warning {
+				continue
+			}
+			mensajes = append(mensajes, fmt.Sprintf("%s:%d: %s", pathInstrucciones, e.Line, e.Message))
+		}
+		http.Error(w, strings.Join(mensajes, "\n"), http.StatusBadRequest)
+		return
+	}
+	for _, e := range errores {
+		log.Printf("PID: %d - %s: %s", pid, pathInstrucciones, e)
+	}
+
 	globals.InstructionsMutex.Lock()
 	defer globals.InstructionsMutex.Unlock()
-	globals.InstruccionesProceso[int(pid)] = instrucciones
+	globals.InstruccionesProceso[int(pid)] = programa.Instructions
+	globals.MapasDeOrigen[int(pid)] = programa.SourceMap
 	fmt.Printf("Instrucciones cargadas para el PID %d ", pid)
 
 	//acá debemos inicializar vacía la tabla de páginas para el proceso
@@ -69,7 +91,8 @@ func CargarInstrucciones(w http.ResponseWriter, r *http.Request) {
 
 	globals.Tablas_de_paginas[int(pid)] = globals.TablaPaginas{}
 	log.Printf("PID: %d - Tamaño de tabla: %d", pid, len(globals.Tablas_de_paginas[int(pid)]))
-	respuesta, err := json.Marshal((BuscarInstruccionMap(int(pc), int(pid))))
+	instruccion, _ := BuscarInstruccionMap(int(pc), int(pid))
+	respuesta, err := json.Marshal(instruccion)
 	if err != nil {
 		http.Error(w, "Error al codificar los datos como JSON", http.StatusInternalServerError)
 		return
@@ -85,24 +108,97 @@ func InstruccionActual(w http.ResponseWriter, r *http.Request) {
 	pid := queryParams.Get("pid")
 	pc := queryParams.Get("pc")
 
-	respuesta, err := json.Marshal((BuscarInstruccionMap(PasarAInt(pc), PasarAInt(pid))))
+	// Las instrucciones y su origen se leen juntos y bajo el mismo mutex con el que los carga CargarInstrucciones
+	globals.InstructionsMutex.Lock()
+	instruccion, existe := BuscarInstruccionMap(PasarAInt(pc), PasarAInt(pid))
+	origen := ""
+	if mapa := globals.MapasDeOrigen[PasarAInt(pid)]; existe && PasarAInt(pc) < len(mapa) {
+		origen = mapa[PasarAInt(pc)].String()
+	}
+	globals.InstructionsMutex.Unlock()
+
+	if !existe {
+		http.Error(w, fmt.Sprintf("PID %s no tiene instrucción %s", pid, pc), http.StatusNotFound)
+		return
+	}
+
+	respuesta, err := json.Marshal(instruccion)
 	if err != nil {
 		http.Error(w, "Error al codificar los datos como JSON", http.StatusInternalServerError)
 		return
 	}
 
-	fmt.Printf("La instruccion buscada para el PID: %s fue: %s", pid, BuscarInstruccionMap(PasarAInt(pc), PasarAInt(pid)))
+	fmt.Printf("La instruccion buscada para el PID: %s fue: %s", pid, instruccion)
 
-	time.Sleep(time.Duration(globals.Configmemory.Delay_response) * time.Millisecond)
+	// El origen de la instrucción (etiqueta y línea del script) viaja en un header para no alterar la respuesta
+	if origen != "" {
+		w.Header().Set("X-Source", origen)
+	}
+
+	clock.Sleep(time.Duration(globals.Configmemory.Delay_response) * time.Millisecond)
 
 	w.WriteHeader(http.StatusOK)
 	w.Write(respuesta)
 
 }
 
-func BuscarInstruccionMap(pc int, pid int) string {
-	resultado := globals.InstruccionesProceso[pid][pc]
-	return resultado
+// Ventana de instrucciones que pide CPU para su caché
+type BloqueInstrucciones_BRS struct {
+	Desde 			int 		`json:"desde"`
+	Instrucciones 	[]string 	`json:"instrucciones"`
+	Origenes 		[]string 	`json:"origenes"`
+}
+
+/**
+ * InstruccionesBloque: Devuelve hasta "cantidad" instrucciones a partir de "pc". Paga el retardo de memoria una sola vez por bloque.
+*/
+func InstruccionesBloque(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	pid := PasarAInt(queryParams.Get("pid"))
+	pc := PasarAInt(queryParams.Get("pc"))
+	cantidad := max(PasarAInt(queryParams.Get("cantidad")), 1)
+
+	globals.InstructionsMutex.Lock()
+	instrucciones := globals.InstruccionesProceso[pid]
+	mapa := globals.MapasDeOrigen[pid]
+	bloque := BloqueInstrucciones_BRS{Desde: pc}
+	for i := pc; i >= 0 && i < len(instrucciones) && i < pc+cantidad; i++ {
+		bloque.Instrucciones = append(bloque.Instrucciones, instrucciones[i])
+		origen := ""
+		if i < len(mapa) {
+			origen = mapa[i].String()
+		}
+		bloque.Origenes = append(bloque.Origenes, origen)
+	}
+	globals.InstructionsMutex.Unlock()
+
+	if len(bloque.Instrucciones) == 0 {
+		http.Error(w, fmt.Sprintf("PID %d no tiene instrucciones desde %d", pid, pc), http.StatusNotFound)
+		return
+	}
+
+	respuesta, err := json.Marshal(bloque)
+	if err != nil {
+		http.Error(w, "Error al codificar los datos como JSON", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("PID: %d - Bloque de instrucciones - Desde: %d - Cantidad: %d", pid, pc, len(bloque.Instrucciones))
+
+	clock.Sleep(time.Duration(globals.Configmemory.Delay_response) * time.Millisecond)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respuesta)
+}
+
+// BuscarInstruccionMap: Instrucción pc del proceso, y si existe. Requiere InstructionsMutex tomado.
+func BuscarInstruccionMap(pc int, pid int) (string, bool) {
+	instrucciones := globals.InstruccionesProceso[pid]
+	if pc < 0 || pc >= len(instrucciones) {
+		return "", false
+	}
+	return instrucciones[pc], true
 }
 
 func PasarAInt(cadena string) int {
@@ -260,7 +356,7 @@ func LeerMemoria(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	time.Sleep(time.Duration(globals.Configmemory.Delay_response) * time.Millisecond) //nos dan los milisegundos o lo dejamos así?
+	clock.Sleep(time.Duration(globals.Configmemory.Delay_response) * time.Millisecond) //nos dan los milisegundos o lo dejamos así?
 
 	w.WriteHeader(http.StatusOK)
 	w.Write(respuesta)
@@ -303,7 +399,7 @@ func EscribirMemoria(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	time.Sleep(time.Duration(globals.Configmemory.Delay_response) * time.Millisecond) 
+	clock.Sleep(time.Duration(globals.Configmemory.Delay_response) * time.Millisecond) 
 
 	fmt.Println("Estado de la memoria: ", globals.User_Memory)
 
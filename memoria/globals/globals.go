@@ -1,9 +1,14 @@
 package globals
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/sisoputnfrba/tp-golang/utils/assembler"
+)
 
 // Global variables:
 var InstruccionesProceso = make(map[int][]string)
+var MapasDeOrigen = make(map[int][]assembler.T_SourceLine)	// Línea y etiqueta del script de cada instrucción, por PID
 
 // Global semaphores
 var (
@@ -16,6 +21,7 @@ type T_ConfigMemory struct {
 	Page_size         int    `json:"page_size"`
 	Instructions_path string `json:"instructions_path"`
 	Delay_response    int    `json:"delay_response"`
+	Clock_url         string `json:"clock_url"`
 }
 
 var Configmemory *T_ConfigMemory
@@ -9,6 +9,7 @@ import (
 	cfg "github.com/sisoputnfrba/tp-golang/utils/config"
 	logger "github.com/sisoputnfrba/tp-golang/utils/log"
 	"github.com/sisoputnfrba/tp-golang/utils/server-Functions"
+	"github.com/sisoputnfrba/tp-golang/utils/clock"
 )
 
 func main() {
@@ -26,6 +27,11 @@ func main() {
 
 	fmt.Println("Configuracion MEMORIA cargada")
 
+	// Con clock_url las demoras usan el reloj virtual de kernel en lugar del tiempo real
+	if globals.Configmemory.Clock_url != "" {
+		clock.UseRemote(globals.Configmemory.Clock_url)
+	}
+
 	globals.User_Memory = make([]byte, globals.Configmemory.Memory_size)
 
 	// Calculo la cantidad de frames que tendrá la memoria
@@ -47,6 +53,7 @@ func RegisteredModuleRoutes() http.Handler {
 		RouteHandlers: map[string]http.HandlerFunc{
 			"GET /instrucciones":      memoria_api.InstruccionActual,
 			"POST /instrucciones":     memoria_api.CargarInstrucciones,
+			"GET /instrucciones/bloque": memoria_api.InstruccionesBloque, // caché de instrucciones de CPU
 			"GET /enviarMarco":        memoria_api.EnviarMarco,      //implementada en la MMU
 			"PATCH /resize":           memoria_api.Resize,           //implementada en CPU
 			"PATCH /finalizarProceso": memoria_api.FinalizarProceso, //falta implementar desde KERNEL
@@ -0,0 +1,140 @@
+package assembler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+)
+
+// Instrucciones de salto y la posición de su operando de destino (contando la instrucción como 0).
+// El destino puede ser una etiqueta, un número de instrucción o un registro.
+var JumpTargets = map[string]func(operandos int) int{
+	"JMP": func(int) int { return 1 },
+	"JZ":  func(int) int { return 1 },
+	"JG":  func(int) int { return 1 },
+	"JL":  func(int) int { return 1 },
+	"JGE": func(int) int { return 1 },
+	"JLE": func(int) int { return 1 },
+	// CALL (Instrucción): Salta guardando la dirección de retorno en la pila
+	"CALL": func(int) int { return 1 },
+	// JNZ acepta la forma vieja (Registro, Instrucción) y la nueva (Instrucción)
+	"JNZ": func(operandos int) int { return operandos },
+}
+
+// Línea de script que define una etiqueta, opcionalmente seguida de una instrucción ("loop:" o "loop: SUM AX BX")
+var labelLine = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*):\s*(.*)$`)
+
+/**
+ * SplitLabel: Separa la etiqueta de una línea de script, si la tiene
+
+ * @param line: línea del script
+ * @return label: etiqueta definida en la línea ("" si no hay)
+ * @return instruction: resto de la línea (vacío si la línea es solo la etiqueta)
+*/
+func SplitLabel(line string) (label string, instruction string) {
+	match := labelLine.FindStringSubmatch(line)
+	if match == nil {
+		return "", line
+	}
+	return match[1], match[2]
+}
+
+// Origen de una instrucción ensamblada: línea del script y etiqueta más cercana que la precede
+type T_SourceLine struct {
+	Line 	int 	`json:"line"`
+	Label 	string 	`json:"label,omitempty"`
+	Offset 	int 	`json:"offset"`
+}
+
+// String: Formato para logs, por ejemplo "loop+2 (línea 7)"
+func (s T_SourceLine) String() string {
+	if s.Label == "" {
+		return fmt.Sprintf("línea %d", s.Line)
+	}
+	if s.Offset == 0 {
+		return fmt.Sprintf("%s (línea %d)", s.Label, s.Line)
+	}
+	return fmt.Sprintf("%s+%d (línea %d)", s.Label, s.Offset, s.Line)
+}
+
+// Script ensamblado: instrucciones con los saltos resueltos a números de instrucción, etiquetas y mapa de origen
+type T_Program struct {
+	Instructions 	[]string
+	Labels 			map[string]int
+	SourceMap 		[]T_SourceLine
+}
+
+/**
+ * Assemble: Quita las etiquetas de un script y reemplaza los destinos de salto simbólicos por números de instrucción
+
+ * @param lines: líneas del script
+ * @return T_Program: script ensamblado
+ * @return error: si hay etiquetas duplicadas o saltos a etiquetas no definidas
+*/
+func Assemble(lines []string) (T_Program, error) {
+	program := T_Program{Labels: make(map[string]int)}
+	label, labelStart := "", 0
+
+	// Primera pasada: etiquetas
+	for i, line := range lines {
+		if name, rest := SplitLabel(line); name != "" {
+			if previous, ok := program.Labels[name]; ok {
+				return T_Program{}, fmt.Errorf("línea %d: etiqueta %q duplicada (definida antes para la instrucción %d)", i+1, name, previous)
+			}
+			if pcb.IsRegister(name) {
+				return T_Program{}, fmt.Errorf("línea %d: la etiqueta %q tiene el nombre de un registro", i+1, name)
+			}
+			program.Labels[name] = len(program.Instructions)
+			label, labelStart = name, len(program.Instructions)
+
+			line = rest
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+		}
+
+		// CPU separa los operandos por un único espacio
+		program.Instructions = append(program.Instructions, strings.Join(strings.Fields(line), " "))
+		program.SourceMap = append(program.SourceMap, T_SourceLine{Line: i + 1, Label: label, Offset: len(program.Instructions) - 1 - labelStart})
+	}
+
+	// Segunda pasada: destinos de salto
+	for i, instruction := range program.Instructions {
+		fields := strings.Fields(instruction)
+		if len(fields) < 2 {
+			continue
+		}
+		target, isJump := JumpTargets[fields[0]]
+		if !isJump {
+			continue
+		}
+
+		position := target(len(fields) - 1)
+		if position < 1 || position >= len(fields) {
+			continue
+		}
+
+		resolved, err := program.resolve(fields[position])
+		if err != nil {
+			return T_Program{}, fmt.Errorf("línea %d: %v", program.SourceMap[i].Line, err)
+		}
+		fields[position] = resolved
+		program.Instructions[i] = strings.Join(fields, " ")
+	}
+
+	return program, nil
+}
+
+// resolve: Traduce un destino de salto. Los números y registros quedan igual; las etiquetas se reemplazan por su instrucción.
+func (p T_Program) resolve(target string) (string, error) {
+	if _, err := strconv.Atoi(target); err == nil || pcb.IsRegister(target) {
+		return target, nil
+	}
+	if index, ok := p.Labels[target]; ok {
+		return strconv.Itoa(index), nil
+	}
+	return "", fmt.Errorf("etiqueta %q no definida", target)
+}
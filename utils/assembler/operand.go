@@ -0,0 +1,91 @@
+package assembler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+)
+
+type OperandKind int
+
+const (
+	OperandRegister 	OperandKind = iota 	// AX, EBX, ...
+	OperandImmediate 						// #5, #0x10 o 5
+	OperandMemory 							// [EBX], [EBX+4], [EBX-4] o [100]
+)
+
+// Operando de una instrucción aritmética o de movimiento, ya interpretado
+type T_Operand struct {
+	Kind 		OperandKind
+	Register 	string 	// registro, o base de la dirección en un operando de memoria ("" si es una dirección absoluta)
+	Value 		uint32 	// valor inmediato, o desplazamiento / dirección absoluta en un operando de memoria
+}
+
+/**
+ * ParseOperand: Interpreta el texto de un operando
+
+ * @param text: operando tal como aparece en la instrucción
+ * @return T_Operand: operando interpretado
+ * @return error: si no es un registro, un inmediato ni una dirección de memoria válida
+*/
+func ParseOperand(text string) (T_Operand, error) {
+	if pcb.IsRegister(text) {
+		return T_Operand{Kind: OperandRegister, Register: text}, nil
+	}
+
+	if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+		return parseMemory(text[1 : len(text)-1])
+	}
+
+	value, err := ParseNumber(strings.TrimPrefix(text, "#"))
+	if err != nil {
+		return T_Operand{}, fmt.Errorf("operando %q inválido", text)
+	}
+	return T_Operand{Kind: OperandImmediate, Value: value}, nil
+}
+
+// parseMemory: Interpreta el contenido de los corchetes: registro, registro+desplazamiento, registro-desplazamiento o dirección
+func parseMemory(content string) (T_Operand, error) {
+	operand := T_Operand{Kind: OperandMemory}
+
+	base, offset, sign := content, "", byte(0)
+	if i := strings.IndexAny(content, "+-"); i > 0 {
+		base, offset, sign = content[:i], content[i+1:], content[i]
+	}
+
+	if !pcb.IsRegister(base) {
+		if offset != "" {
+			return T_Operand{}, fmt.Errorf("dirección [%s] inválida: la base debe ser un registro", content)
+		}
+		address, err := ParseNumber(base)
+		if err != nil {
+			return T_Operand{}, fmt.Errorf("dirección [%s] inválida", content)
+		}
+		operand.Value = address
+		return operand, nil
+	}
+
+	operand.Register = base
+	if offset != "" {
+		value, err := ParseNumber(offset)
+		if err != nil {
+			return T_Operand{}, fmt.Errorf("desplazamiento de [%s] inválido", content)
+		}
+		if sign == '-' {
+			value = -value
+		}
+		operand.Value = value
+	}
+	return operand, nil
+}
+
+// ParseNumber: Acepta decimal, hexadecimal (0x) y negativos, que quedan en complemento a dos
+func ParseNumber(text string) (uint32, error) {
+	value, err := strconv.ParseInt(text, 0, 64)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(value), nil
+}
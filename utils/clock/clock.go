@@ -0,0 +1,496 @@
+package clock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Reloj que consultan todos los módulos para sus demoras. Por defecto es el tiempo real.
+type T_Clock interface {
+	Sleep(d time.Duration)
+	Now() time.Time
+	// wait: Espera la duración indicada o hasta que se cierre cancel. Devuelve true si la espera venció,
+	// y en ese caso quien esperaba queda ocupado (ver Busy) hasta que llame a idle.
+	wait(d time.Duration, cancel <-chan struct{}) bool
+	busy()
+	idle()
+}
+
+var (
+	current 	T_Clock = realClock{}
+	currentMutex 	sync.RWMutex
+)
+
+/**
+ * UseVirtual: Usa un reloj virtual local (el módulo que lo hospeda)
+
+ * @param vc: reloj virtual
+*/
+func UseVirtual(vc *T_VirtualClock) {
+	set(vc)
+}
+
+/**
+ * UseRemote: Usa el reloj virtual que hospeda otro módulo
+
+ * @param url: dirección base del módulo que hospeda el reloj (ej: http://127.0.0.1:8001)
+*/
+func UseRemote(url string) {
+	set(remoteClock{url: url})
+	log.Printf("Reloj virtual remoto: %s", url)
+}
+
+func set(c T_Clock) {
+	currentMutex.Lock()
+	defer currentMutex.Unlock()
+	current = c
+}
+
+func get() T_Clock {
+	currentMutex.RLock()
+	defer currentMutex.RUnlock()
+	return current
+}
+
+// Sleep: Espera la duración indicada según el reloj en uso
+func Sleep(d time.Duration) {
+	get().Sleep(d)
+}
+
+// Now: Hora actual según el reloj en uso
+func Now() time.Time {
+	return get().Now()
+}
+
+// Since: Tiempo transcurrido desde t según el reloj en uso
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
+
+// Busy: El módulo empieza una actividad (una ráfaga de CPU, una operación de IO). El reloj virtual no avanza
+// mientras haya actividades en curso, salvo las que están esperando en Sleep.
+func Busy() {
+	get().busy()
+}
+
+// Idle: Termina una actividad empezada con Busy
+func Idle() {
+	get().idle()
+}
+
+// Timer que se puede cancelar, o pausar y reanudar, antes de que venza, sin importar el reloj en uso
+type Timer struct {
+	mutex 		sync.Mutex
+	f 			func()
+	remaining 	time.Duration 	// lo que falta para que venza, sin contar el tiempo que estuvo pausado
+	startedAt 	time.Time 		// desde cuándo corre la espera actual
+	running 	bool
+	stopped 	bool
+	fired 		bool
+	done 		chan struct{} 	// se cierra al cancelarlo o pausarlo, para despertar a la goroutine que espera
+}
+
+/**
+ * AfterFunc: Ejecuta f en su propia goroutine cuando pasa la duración indicada según el reloj en uso
+
+ * @param d: duración
+ * @param f: función a ejecutar
+ * @return *Timer: timer para cancelar, pausar o reanudar la ejecución
+*/
+func AfterFunc(d time.Duration, f func()) *Timer {
+	timer := &Timer{f: f, remaining: d}
+	timer.mutex.Lock()
+	timer.start()
+	timer.mutex.Unlock()
+	return timer
+}
+
+// start: Empieza a esperar lo que le queda al timer. Requiere el mutex del timer.
+func (t *Timer) start() {
+	done := make(chan struct{})
+	t.done = done
+	t.running = true
+	t.startedAt = Now()
+
+	remaining := t.remaining
+	go func() {
+		if !get().wait(remaining, done) {
+			return
+		}
+		// Hasta que f termine, el reloj virtual no sigue avanzando
+		defer Idle()
+
+		t.mutex.Lock()
+		// Se pausó o canceló mientras vencía: la espera ya no es la vigente
+		if t.done != done || !t.running {
+			t.mutex.Unlock()
+			return
+		}
+		t.running = false
+		t.fired = true
+		t.mutex.Unlock()
+		t.f()
+	}()
+}
+
+// Stop: Cancela el timer. Devuelve false si ya había vencido o había sido cancelado.
+func (t *Timer) Stop() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.stopped || t.fired {
+		return false
+	}
+	t.stopped = true
+	if t.running {
+		t.running = false
+		close(t.done)
+	}
+	return true
+}
+
+// Pause: Frena la cuenta del timer, que conserva lo que le faltaba. Devuelve false si no estaba corriendo.
+func (t *Timer) Pause() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if !t.running {
+		return false
+	}
+	t.running = false
+	t.remaining = max(t.remaining-Since(t.startedAt), 0)
+	close(t.done)
+	return true
+}
+
+// Resume: Retoma la cuenta de un timer pausado. Devuelve false si no estaba pausado.
+func (t *Timer) Resume() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.running || t.stopped || t.fired {
+		return false
+	}
+	t.start()
+	return true
+}
+
+// --------------------- TIEMPO REAL ------------------------
+
+type realClock struct{}
+
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+func (realClock) Now() time.Time        { return time.Now() }
+func (realClock) busy()                 {}
+func (realClock) idle()                 {}
+
+func (realClock) wait(d time.Duration, cancel <-chan struct{}) bool {
+	return realClock{}.sleep(d, cancel)
+}
+
+func (realClock) sleep(d time.Duration, cancel <-chan struct{}) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-cancel:
+		return false
+	}
+}
+
+// --------------------- RELOJ VIRTUAL ------------------------
+
+// Instante cero del reloj virtual
+var epoch = time.Unix(0, 0)
+
+type sleeper struct {
+	wakeAt 	time.Duration
+	seq 	uint64
+	done 	chan struct{}
+	holds 	bool 	// al despertar vuelve a contar como actividad en curso
+}
+
+/**
+ * Reloj virtual: el tiempo no corre solo, sino que salta al próximo vencimiento cuando ningún módulo tiene actividades en curso.
+ * Cada módulo avisa cuándo empieza y termina una actividad (Busy / Idle); una actividad que espera en Sleep no cuenta
+ * mientras espera, y un timer que vence cuenta hasta que termina su función.
+ * Settle es un margen de tiempo real sin actividad antes de avanzar, para los pasos entre goroutines de un mismo módulo.
+ * Los que esperan se despiertan de a uno, en orden de vencimiento y, ante empate, de llegada, para que el orden sea siempre el mismo.
+*/
+type T_VirtualClock struct {
+	mutex 			sync.Mutex
+	now 			time.Duration
+	seq 			uint64
+	sleepers 		[]*sleeper
+	active 			int 		// actividades en curso en todos los módulos
+	lastActivity 	time.Time
+	Settle 			time.Duration
+}
+
+/**
+ * NewVirtualClock: Crea un reloj virtual y empieza a avanzarlo
+
+ * @param settle: tiempo real sin actividad que se espera antes de avanzar el reloj
+ * @return *T_VirtualClock: reloj virtual
+*/
+func NewVirtualClock(settle time.Duration) *T_VirtualClock {
+	if settle <= 0 {
+		settle = 5 * time.Millisecond
+	}
+	vc := &T_VirtualClock{Settle: settle, lastActivity: time.Now()}
+	go vc.advance()
+	log.Printf("Reloj virtual iniciado (margen: %v)", settle)
+	return vc
+}
+
+func (vc *T_VirtualClock) Now() time.Time {
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+	vc.lastActivity = time.Now()
+	return epoch.Add(vc.now)
+}
+
+// Sleep: Mientras espera, la actividad que lo llamó no cuenta como en curso
+func (vc *T_VirtualClock) Sleep(d time.Duration) {
+	vc.sleep(d, nil)
+}
+
+// sleep: Espera cediendo la actividad en curso. Devuelve false si se canceló antes de vencer, y entonces la actividad se retoma.
+func (vc *T_VirtualClock) sleep(d time.Duration, cancel <-chan struct{}) bool {
+	vc.mutex.Lock()
+	holds := vc.active > 0
+	if holds {
+		vc.active--
+	}
+	s := vc.register(d, holds)
+	vc.mutex.Unlock()
+
+	select {
+	case <-s.done:
+		return true
+	case <-cancel:
+		if vc.unregister(s) && holds {
+			vc.busy()
+		}
+		return false
+	}
+}
+
+// wait: Espera de un timer. Al vencer cuenta como actividad hasta que su función termine (AfterFunc llama a Idle).
+func (vc *T_VirtualClock) wait(d time.Duration, cancel <-chan struct{}) bool {
+	vc.mutex.Lock()
+	s := vc.register(d, true)
+	vc.mutex.Unlock()
+
+	select {
+	case <-s.done:
+		return true
+	case <-cancel:
+		// Si ya había vencido, la actividad ya se contó: se informa como vencida para que AfterFunc la cierre
+		return !vc.unregister(s)
+	}
+}
+
+func (vc *T_VirtualClock) busy() {
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+	vc.active++
+	vc.lastActivity = time.Now()
+}
+
+func (vc *T_VirtualClock) idle() {
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+	if vc.active == 0 {
+		log.Println("Reloj virtual: Idle sin una actividad en curso")
+		return
+	}
+	vc.active--
+	vc.lastActivity = time.Now()
+}
+
+// register: Anota una espera. Su canal done se cierra cuando vence. Requiere el mutex del reloj.
+func (vc *T_VirtualClock) register(d time.Duration, holds bool) *sleeper {
+	vc.seq++
+	s := &sleeper{wakeAt: vc.now + max(d, 0), seq: vc.seq, done: make(chan struct{}), holds: holds}
+	vc.sleepers = append(vc.sleepers, s)
+	sort.Slice(vc.sleepers, func(i, j int) bool {
+		if vc.sleepers[i].wakeAt != vc.sleepers[j].wakeAt {
+			return vc.sleepers[i].wakeAt < vc.sleepers[j].wakeAt
+		}
+		return vc.sleepers[i].seq < vc.sleepers[j].seq
+	})
+	vc.lastActivity = time.Now()
+	return s
+}
+
+// unregister: Quita una espera cancelada, para que el reloj no avance hasta su vencimiento. Devuelve false si ya había vencido.
+func (vc *T_VirtualClock) unregister(s *sleeper) bool {
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+
+	for i, other := range vc.sleepers {
+		if other == s {
+			vc.sleepers = append(vc.sleepers[:i], vc.sleepers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// advance: Cuando no hay actividades en curso, salta al vencimiento más próximo y despierta a quien lo espera
+func (vc *T_VirtualClock) advance() {
+	for {
+		time.Sleep(vc.Settle)
+
+		vc.mutex.Lock()
+		if len(vc.sleepers) > 0 && vc.active == 0 && time.Since(vc.lastActivity) >= vc.Settle {
+			next := vc.sleepers[0]
+			vc.sleepers = vc.sleepers[1:]
+			vc.now = max(vc.now, next.wakeAt)
+			// El que despierta ya está en curso antes de que el reloj pueda volver a avanzar
+			if next.holds {
+				vc.active++
+			}
+			vc.lastActivity = time.Now()
+			close(next.done)
+		}
+		vc.mutex.Unlock()
+	}
+}
+
+type T_ClockNow struct {
+	Now int64 `json:"now"`
+}
+
+type T_ClockSleep struct {
+	Ms int64 `json:"ms"`
+}
+
+/**
+ * HandleNow: Devuelve la hora virtual en milisegundos
+*/
+func (vc *T_VirtualClock) HandleNow(w http.ResponseWriter, r *http.Request) {
+	now := vc.Now().Sub(epoch).Milliseconds()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(T_ClockNow{Now: now})
+}
+
+/**
+ * HandleSleep: Responde recién cuando venció la espera pedida, con la hora virtual en milisegundos.
+ * Es un Sleep del módulo que la pide: su actividad no cuenta mientras espera.
+ * Si el que la pidió corta la conexión (canceló su timer), la espera se descarta.
+*/
+func (vc *T_VirtualClock) HandleSleep(w http.ResponseWriter, r *http.Request) {
+	var request T_ClockSleep
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !vc.sleep(time.Duration(request.Ms)*time.Millisecond, r.Context().Done()) {
+		return
+	}
+	vc.HandleNow(w, r)
+}
+
+// HandleBusy: Otro módulo empieza una actividad (ver Busy)
+func (vc *T_VirtualClock) HandleBusy(w http.ResponseWriter, r *http.Request) {
+	vc.busy()
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleIdle: Otro módulo termina una actividad (ver Idle)
+func (vc *T_VirtualClock) HandleIdle(w http.ResponseWriter, r *http.Request) {
+	vc.idle()
+	w.WriteHeader(http.StatusOK)
+}
+
+// --------------------- RELOJ REMOTO ------------------------
+
+type remoteClock struct {
+	url string
+}
+
+func (rc remoteClock) Sleep(d time.Duration) {
+	rc.sleep(d, nil)
+}
+
+// Un timer remoto espera como un Sleep del módulo y, al vencer, cuenta como actividad hasta que termina su función
+func (rc remoteClock) wait(d time.Duration, cancel <-chan struct{}) bool {
+	if !rc.sleep(d, cancel) {
+		return false
+	}
+	rc.busy()
+	return true
+}
+
+func (rc remoteClock) busy() {
+	rc.post("busy")
+}
+
+func (rc remoteClock) idle() {
+	rc.post("idle")
+}
+
+// post: Avisa al reloj remoto que empieza o termina una actividad. Si no responde, el aviso se pierde.
+func (rc remoteClock) post(path string) {
+	resp, err := http.Post(fmt.Sprintf("%s/clock/%s", rc.url, path), "application/json", nil)
+	if err != nil {
+		log.Printf("Reloj virtual no disponible: %v", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// Si el reloj remoto no responde se usa el tiempo real, para no frenar al módulo.
+// Cancelar corta el pedido, y el módulo que hospeda el reloj descarta la espera.
+func (rc remoteClock) sleep(d time.Duration, cancel <-chan struct{}) bool {
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go func() {
+		select {
+		case <-cancel:
+			stop()
+		case <-ctx.Done():
+		}
+	}()
+
+	body, _ := json.Marshal(T_ClockSleep{Ms: d.Milliseconds()})
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/clock/sleep", rc.url), bytes.NewBuffer(body))
+	if err != nil {
+		return realClock{}.sleep(d, cancel)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		log.Printf("Reloj virtual no disponible, se espera en tiempo real: %v", err)
+		return realClock{}.sleep(d, cancel)
+	}
+	resp.Body.Close()
+	return true
+}
+
+func (rc remoteClock) Now() time.Time {
+	resp, err := http.Get(fmt.Sprintf("%s/clock", rc.url))
+	if err != nil {
+		return time.Now()
+	}
+	defer resp.Body.Close()
+
+	var now T_ClockNow
+	if err := json.NewDecoder(resp.Body).Decode(&now); err != nil {
+		return time.Now()
+	}
+	return epoch.Add(time.Duration(now.Now) * time.Millisecond)
+}
@@ -0,0 +1,133 @@
+/**
+ * algo-lint: Valida scripts de instrucciones sin levantar los módulos.
+ *
+ * Uso: go run ./utils/cmd/algo-lint [-kernel kernel/config_kernel.json] [-io entradasalida/config] script...
+ *
+ * Con -kernel se validan los recursos contra los de la configuración de kernel y con -io las interfaces
+ * (nombre de archivo o pool, y tipo) contra las configuraciones de entradasalida. Un recurso o interfaz desconocido es una advertencia,
+ * porque se pueden crear o conectar en tiempo de ejecución. Sale con 1 si algún script tiene errores.
+*/
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cfg "github.com/sisoputnfrba/tp-golang/utils/config"
+	"github.com/sisoputnfrba/tp-golang/utils/validator"
+)
+
+type kernelConfig struct {
+	Resources []string `json:"resources"`
+}
+
+type ioConfig struct {
+	Type string `json:"type"`
+	Pool string `json:"pool"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run: Valida los scripts indicados en los argumentos y devuelve el código de salida (0 sin errores, 1 con errores, 2 uso incorrecto)
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("algo-lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	kernelPath := flags.String("kernel", "", "configuración de kernel, para validar los recursos")
+	ioDir := flags.String("io", "", "directorio con las configuraciones de entradasalida, para validar las interfaces")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "uso: algo-lint [-kernel config_kernel.json] [-io dir] script...")
+		return 2
+	}
+
+	ctx, err := loadContext(*kernelPath, *ioDir)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	failed := false
+	for _, path := range flags.Args() {
+		lines, err := readLines(path)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			failed = true
+			continue
+		}
+
+		_, diagnostics := validator.Validate(lines, ctx)
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Warning {
+				fmt.Fprintf(stdout, "%s:%d: advertencia: %s\n", path, diagnostic.Line, diagnostic.Message)
+			} else {
+				fmt.Fprintf(stdout, "%s:%d: %s\n", path, diagnostic.Line, diagnostic.Message)
+			}
+		}
+		failed = failed || validator.HasErrors(diagnostics)
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// loadContext: Arma los recursos e interfaces conocidos a partir de las configuraciones indicadas
+func loadContext(kernelPath string, ioDir string) (validator.T_Context, error) {
+	var ctx validator.T_Context
+
+	if kernelPath != "" {
+		var kernel kernelConfig
+		if err := cfg.ConfigInit(kernelPath, &kernel); err != nil {
+			return ctx, fmt.Errorf("%s: %v", kernelPath, err)
+		}
+		ctx.Resources = append([]string{}, kernel.Resources...)
+	}
+
+	if ioDir != "" {
+		paths, err := filepath.Glob(filepath.Join(ioDir, "*.config"))
+		if err != nil {
+			return ctx, err
+		}
+		ctx.Interfaces = make(map[string]string)
+		for _, path := range paths {
+			var io ioConfig
+			if err := cfg.ConfigInit(path, &io); err != nil {
+				return ctx, fmt.Errorf("%s: %v", path, err)
+			}
+			// Igual que entradasalida: el nombre de la interfaz es el del archivo de configuración
+			name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			ctx.Interfaces[name] = io.Type
+			if io.Pool != "" {
+				ctx.Interfaces[io.Pool] = io.Type
+			}
+		}
+	}
+
+	return ctx, nil
+}
+
+func readLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func bundledScripts(t *testing.T) []string {
+	t.Helper()
+	var scripts []string
+	for _, pattern := range []string{"our/*.txt", "preliminares/*", "scripts_memoria/*"} {
+		matches, err := filepath.Glob(filepath.Join("../../../algo-pruebas", pattern))
+		if err != nil {
+			t.Fatal(err)
+		}
+		scripts = append(scripts, matches...)
+	}
+	if len(scripts) == 0 {
+		t.Fatal("no se encontraron scripts en algo-pruebas")
+	}
+	return scripts
+}
+
+// Con las configuraciones del repo, como indica el README de algo-pruebas, ningún script tiene errores
+func TestBundledScriptsPass(t *testing.T) {
+	args := append([]string{"-kernel", "../../../kernel/config_kernel.json", "-io", "../../../entradasalida/config"}, bundledScripts(t)...)
+
+	var stdout, stderr bytes.Buffer
+	if code := run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("algo-lint salió con %d:\n%s%s", code, stdout.String(), stderr.String())
+	}
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		if line != "" && !strings.Contains(line, "advertencia:") {
+			t.Errorf("diagnóstico que no es advertencia: %s", line)
+		}
+	}
+}
+
+func TestScriptWithErrorsFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "malo.txt")
+	if err := os.WriteFile(path, []byte("SET AX 1\nFOO\nJZ fin\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{path}, &stdout, &stderr); code != 1 {
+		t.Fatalf("algo-lint salió con %d, se esperaba 1", code)
+	}
+	for _, expected := range []string{path + ":2: ", path + ":3: "} {
+		if !strings.Contains(stdout.String(), expected) {
+			t.Errorf("falta el diagnóstico %q en:\n%s", expected, stdout.String())
+		}
+	}
+}
+
+func TestUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, &stdout, &stderr); code != 2 {
+		t.Fatalf("sin scripts salió con %d, se esperaba 2", code)
+	}
+	if code := run([]string{"-kernel", "no-existe.json", "x.txt"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("con una configuración inexistente salió con %d, se esperaba 2", code)
+	}
+}
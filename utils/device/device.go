@@ -5,4 +5,5 @@ type T_IOInterface struct {
 	InterfaceType string `json:"interfaceType"`
 	InterfaceIP   string `json:"interfaceIP"`
 	InterfacePort int    `json:"interfacePort"`
+	InterfacePool string `json:"interfacePool"`
 }
\ No newline at end of file
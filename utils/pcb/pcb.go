@@ -5,16 +5,83 @@ type T_PCB struct {
 	PID 				uint32 						`json:"pid"`
 	PC 					uint32 						`json:"pc"`
 	Quantum 			uint32 						`json:"quantum"`
+	RemainingQuantum 	uint32 						`json:"remaining_quantum"`
+	SchedulingClass 	string 						`json:"scheduling_class"`
 	CPU_reg 			map[string]interface{} 		`json:"cpu_reg"`	
-	State 				string 						`json:"state"`
-	EvictionReason 		string  					`json:"eviction_reason"`
+	State 				State 						`json:"state"`
+	EvictionReason 		EvictionReason  			`json:"eviction_reason"`
 	Resources 			map[string]int				`json:"resources"`
 	RequestedResource 	string 						`json:"requested_resource"`
 	Executions 			int 						`json:"executions"`
+	Tickets 			int 						`json:"tickets"`
+	BorrowedTickets 	int 						`json:"borrowed_tickets"`
+	TicketsLentTo 		uint32 						`json:"tickets_lent_to"`
+	Pass 				uint64 						`json:"pass"`
+	CPUTime 			uint64 						`json:"cpu_time"`
+	MaxCPUTime 			uint64 						`json:"max_cpu_ms"`
+	Instructions 		uint64 						`json:"instructions"`
+	MaxInstructions 	uint64 						`json:"max_instructions"`
+	DispatchInstructions uint64 					`json:"dispatch_instructions"`
+	SleepTime 			uint32 						`json:"sleep_time"`
+	BlockedBy 			string 						`json:"blocked_by"`
+	WaitTimeout 		uint32 						`json:"wait_timeout"`
+	StatusRegister 		string 						`json:"status_register"`
+	Priority 			int 						`json:"priority"`
+	InheritedPriority 	int 						`json:"inherited_priority"`
+	PriorityInheritedFrom uint32 					`json:"priority_inherited_from"`
+	Syscall 			*T_Syscall 					`json:"syscall,omitempty"`
+	StackBase 			uint32 						`json:"stack_base"`
+	StackLimit 			uint32 						`json:"stack_limit"`
+	Exception 			string 						`json:"exception,omitempty"`
+}
+
+// TimeSlice: Quantum de la próxima ráfaga. Lo que le quedó de una ráfaga anterior (VRR) o, si no, su quantum base
+func (p T_PCB) TimeSlice() uint32 {
+	if p.RemainingQuantum > 0 {
+		return p.RemainingQuantum
+	}
+	return p.Quantum
+}
+
+// EffectiveTickets: Tickets propios más los prestados por procesos bloqueados esperando un recurso que éste posee
+func (p T_PCB) EffectiveTickets() int {
+	return p.Tickets + p.BorrowedTickets
+}
+
+// EffectivePriority: Prioridad propia o la heredada de un proceso que espera un recurso que éste posee, la que sea mayor
+func (p T_PCB) EffectivePriority() int {
+	return max(p.Priority, p.InheritedPriority)
+}
+
+// LimitExceeded: Indica si el proceso superó su límite de tiempo de CPU o de instrucciones ejecutadas (0 = sin límite)
+func (p T_PCB) LimitExceeded() bool {
+	return (p.MaxCPUTime > 0 && p.CPUTime >= p.MaxCPUTime) ||
+		(p.MaxInstructions > 0 && p.Instructions >= p.MaxInstructions)
+}
+
+// Bits del registro FLAGS, que actualizan CMP y las instrucciones aritméticas y lógicas
+const (
+	FlagZero 		uint8 = 1 << iota	// El resultado fue 0
+	FlagCarry 							// Acarreo / préstamo sin signo (en CMP: destino < origen)
+	FlagSign 							// Bit más significativo del resultado encendido
+	FlagOverflow 						// Desborde con signo
+)
+
+// Registros de CPU que viajan en CPU_reg
+var Registers = []string{"AX", "BX", "CX", "DX", "EAX", "EBX", "ECX", "EDX", "SI", "DI", "PC", "FLAGS", "SP"}
+
+// IsRegister: Indica si el nombre corresponde a un registro de CPU
+func IsRegister(name string) bool {
+	for _, reg := range Registers {
+		if reg == name {
+			return true
+		}
+	}
+	return false
 }
 
 func TipoReg(reg string) string {
-	if reg == "AX" || reg == "BX" || reg == "CX" || reg == "DX" {
+	if reg == "AX" || reg == "BX" || reg == "CX" || reg == "DX" || reg == "FLAGS" {
 		return "uint8"
 	} else {
 		return "uint32"
@@ -0,0 +1,163 @@
+package pcb
+
+import "fmt"
+
+// Estado de un proceso. Se serializa como string en el PCB que viaja entre kernel y CPU.
+type State string
+
+const (
+	StateNew 			State = "NEW"
+	StateReady 			State = "READY"
+	StateExec 			State = "EXEC"
+	StateBlocked 		State = "BLOCKED"
+	StatePaused 		State = "PAUSED"
+	StateTerminated 	State = "TERMINATED"
+)
+
+// Transiciones permitidas desde cada estado. Volver al mismo estado siempre está permitido.
+var transitions = map[State][]State{
+	StateNew: 			{StateReady, StatePaused, StateTerminated},
+	StateReady: 		{StateExec, StatePaused, StateTerminated},
+	StateExec: 			{StateReady, StateBlocked, StatePaused, StateTerminated},
+	StateBlocked: 		{StateReady, StatePaused, StateTerminated},
+	StatePaused: 		{StateReady, StateTerminated},
+	StateTerminated: 	{},
+}
+
+// Valid: Indica si el estado es uno de los conocidos
+func (s State) Valid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// CanTransitionTo: Indica si un proceso puede pasar del estado actual al indicado
+func (s State) CanTransitionTo(next State) bool {
+	if s == next {
+		return true
+	}
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *State) UnmarshalText(text []byte) error {
+	state := State(text)
+	if !state.Valid() {
+		return fmt.Errorf("estado de proceso desconocido: %q", text)
+	}
+	*s = state
+	return nil
+}
+
+// Motivo por el que CPU devuelve un proceso a kernel
+type EvictionReason string
+
+const (
+	ReasonNone 				EvictionReason = ""
+	ReasonExit 				EvictionReason = "EXIT"
+	ReasonIOGen 			EvictionReason = "BLOCKED_IO_GEN"
+	ReasonIOStdin 			EvictionReason = "BLOCKED_IO_STDIN"
+	ReasonIOStdout 			EvictionReason = "BLOCKED_IO_STDOUT"
+	ReasonIODialFS 			EvictionReason = "BLOCKED_IO_DIALFS"
+	ReasonSleep 			EvictionReason = "BLOCKED_SLEEP"
+	ReasonWait 				EvictionReason = "WAIT"
+	ReasonWaitTimeout 		EvictionReason = "WAIT_TIMEOUT"
+	ReasonTryWait 			EvictionReason = "TRY_WAIT"
+	ReasonSignal 			EvictionReason = "SIGNAL"
+	ReasonOutOfMemory 		EvictionReason = "OUT_OF_MEMORY"
+	ReasonLimitExceeded 	EvictionReason = "LIMIT_EXCEEDED"
+	ReasonDivisionByZero 	EvictionReason = "DIVISION_BY_ZERO"
+	ReasonStackOverflow 	EvictionReason = "STACK_OVERFLOW"
+	ReasonStackUnderflow 	EvictionReason = "STACK_UNDERFLOW"
+	ReasonInvalidInstruction EvictionReason = "INVALID_INSTRUCTION"
+	ReasonInvalidRegister 	EvictionReason = "INVALID_REGISTER"
+	ReasonTimeout 			EvictionReason = "TIMEOUT"
+	ReasonPaused 			EvictionReason = "PAUSED"
+	ReasonInterruptedByUser EvictionReason = "INTERRUPTED_BY_USER"
+	ReasonIODisconnected 	EvictionReason = "IO_DISCONNECTED"
+	ReasonResourceDeleted 	EvictionReason = "RESOURCE_DELETED"
+	ReasonInvalidSyscall 	EvictionReason = "INVALID_SYSCALL"
+	ReasonMemoryUnavailable EvictionReason = "MEMORY_UNAVAILABLE"
+)
+
+// Motivos que provoca el propio proceso (instrucciones o límites). Una interrupción no puede pisarlos.
+var selfEvictions = map[EvictionReason]struct{}{
+	ReasonExit: 			{},
+	ReasonIOGen: 			{},
+	ReasonIOStdin: 			{},
+	ReasonIOStdout: 		{},
+	ReasonIODialFS: 		{},
+	ReasonSleep: 			{},
+	ReasonWait: 			{},
+	ReasonWaitTimeout: 		{},
+	ReasonTryWait: 			{},
+	ReasonSignal: 			{},
+	ReasonOutOfMemory: 		{},
+	ReasonLimitExceeded: 	{},
+	ReasonDivisionByZero: 	{},
+	ReasonStackOverflow: 	{},
+	ReasonStackUnderflow: 	{},
+	ReasonInvalidInstruction: {},
+	ReasonInvalidRegister: 	{},
+	ReasonMemoryUnavailable: {},
+}
+
+// Motivos que terminan al proceso
+var terminalEvictions = map[EvictionReason]struct{}{
+	ReasonExit: 			{},
+	ReasonDivisionByZero: 	{},
+	ReasonStackOverflow: 	{},
+	ReasonStackUnderflow: 	{},
+	ReasonInvalidInstruction: {},
+	ReasonInvalidRegister: 	{},
+	ReasonIODisconnected: 	{},
+	ReasonResourceDeleted: 	{},
+	ReasonInvalidSyscall: 	{},
+	ReasonLimitExceeded: 	{},
+	ReasonInterruptedByUser: {},
+}
+
+// Motivos con los que kernel finaliza a un proceso que no está en CPU
+var kernelTerminations = map[EvictionReason]struct{}{
+	ReasonIODisconnected: 	{},
+	ReasonResourceDeleted: 	{},
+	ReasonInvalidSyscall: 	{},
+}
+
+// Motivos que llegan por interrupción desde kernel
+var interruptEvictions = map[EvictionReason]struct{}{
+	ReasonTimeout: 				{},
+	ReasonPaused: 				{},
+	ReasonInterruptedByUser: 	{},
+}
+
+// SelfEviction: Indica si el motivo lo provocó el propio proceso y por lo tanto una interrupción no debe pisarlo
+func (r EvictionReason) SelfEviction() bool {
+	_, ok := selfEvictions[r]
+	return ok
+}
+
+// Terminates: Indica si el motivo termina al proceso (EXIT, un error de ejecución, un límite agotado o la finalización pedida por el usuario)
+func (r EvictionReason) Terminates() bool {
+	_, ok := terminalEvictions[r]
+	return ok
+}
+
+// Valid: Indica si el motivo es uno de los conocidos (o ninguno)
+func (r EvictionReason) Valid() bool {
+	_, interrupt := interruptEvictions[r]
+	_, kernel := kernelTerminations[r]
+	return r == ReasonNone || interrupt || kernel || r.SelfEviction()
+}
+
+func (r *EvictionReason) UnmarshalText(text []byte) error {
+	reason := EvictionReason(text)
+	if !reason.Valid() {
+		return fmt.Errorf("motivo de desalojo desconocido: %q", text)
+	}
+	*r = reason
+	return nil
+}
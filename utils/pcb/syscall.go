@@ -0,0 +1,19 @@
+package pcb
+
+// Llamada a sistema de IO que el proceso deja pedida al desalojarse. Viaja dentro del PCB, junto con el contexto.
+type T_Syscall struct {
+	Name 		string 				`json:"name"`
+	Interface 	string 				`json:"interface"`
+	SleepTime 	int 				`json:"sleep_time,omitempty"`
+	Addresses 	[]DireccionTamanio 	`json:"addresses,omitempty"`
+	Size 		int 				`json:"size,omitempty"`
+	FileName 	string 				`json:"file_name,omitempty"`
+	Pointer 	int 				`json:"pointer,omitempty"`
+	Operation 	string 				`json:"operation,omitempty"`
+}
+
+// Dirección física y cantidad de bytes a partir de ella
+type DireccionTamanio struct {
+	DireccionFisica 	int
+	Tamanio         	int
+}
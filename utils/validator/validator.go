@@ -0,0 +1,415 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sisoputnfrba/tp-golang/utils/assembler"
+	"github.com/sisoputnfrba/tp-golang/utils/pcb"
+)
+
+// Error (o advertencia) encontrado en una línea del script
+type T_Diagnostic struct {
+	Line 		int 	`json:"line"`
+	Message 	string 	`json:"message"`
+	Warning 	bool 	`json:"warning,omitempty"` // no impide ejecutar el script
+}
+
+func (d T_Diagnostic) String() string {
+	if d.Warning {
+		return fmt.Sprintf("línea %d: advertencia: %s", d.Line, d.Message)
+	}
+	return fmt.Sprintf("línea %d: %s", d.Line, d.Message)
+}
+
+// HasErrors: Indica si entre los diagnósticos hay alguno que no sea advertencia
+func HasErrors(diagnostics []T_Diagnostic) bool {
+	for _, diagnostic := range diagnostics {
+		if !diagnostic.Warning {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * Lo que se conoce del sistema para validar nombres. Un campo nil no se valida:
+ * por ejemplo, al crear un proceso las interfaces todavía pueden no haberse conectado.
+*/
+type T_Context struct {
+	Resources 	[]string 			// recursos que conoce kernel
+	Interfaces 	map[string]string 	// nombre de interfaz (o de pool) -> tipo (GENERICA, STDIN, STDOUT, DIALFS)
+}
+
+// Tipos de operando
+type kind int
+
+const (
+	kRegister 	kind = iota 	// nombre de registro
+	kValue 					// registro o número
+	kOperand 				// registro, inmediato o memoria (ver assembler.ParseOperand)
+	kDest 					// registro o memoria
+	kNumber 				// número
+	kTarget 				// destino de salto: etiqueta, número de instrucción o registro
+	kResource 				// nombre de recurso
+	kFile 					// nombre de archivo
+	kGeneric 				// interfaz GENERICA
+	kStdin 					// interfaz STDIN
+	kStdout 				// interfaz STDOUT
+	kDialFS 				// interfaz DIALFS
+)
+
+// Tipo de interfaz que requiere cada tipo de operando de interfaz
+var interfaceTypes = map[kind]string{
+	kGeneric: 	"GENERICA",
+	kStdin: 	"STDIN",
+	kStdout: 	"STDOUT",
+	kDialFS: 	"DIALFS",
+}
+
+// Formas válidas de cada instrucción (algunas aceptan más de una cantidad de operandos)
+var instructions = map[string][][]kind{
+	"EXIT": 			{{}},
+	"SET": 				{{kDest, kOperand}},
+	"SUM": 				{{kDest, kOperand}},
+	"SUB": 				{{kDest, kOperand}},
+	"MUL": 				{{kDest, kOperand}},
+	"DIV": 				{{kDest, kOperand}},
+	"MOD": 				{{kDest, kOperand}},
+	"AND": 				{{kDest, kOperand}},
+	"OR": 				{{kDest, kOperand}},
+	"XOR": 				{{kDest, kOperand}},
+	"SHL": 				{{kDest, kOperand}},
+	"SHR": 				{{kDest, kOperand}},
+	"NOT": 				{{kDest}},
+	"CMP": 				{{kOperand, kOperand}},
+	"MOV_IN": 			{{kRegister, kOperand}},
+	"MOV_OUT": 			{{kOperand, kOperand}},
+	"COPY_STRING": 		{{kNumber}},
+	"RESIZE": 			{{kNumber}},
+	"JMP": 				{{kTarget}},
+	"JZ": 				{{kTarget}},
+	"JNZ": 				{{kTarget}, {kValue, kTarget}},
+	"JG": 				{{kTarget}},
+	"JL": 				{{kTarget}},
+	"JGE": 				{{kTarget}},
+	"JLE": 				{{kTarget}},
+	"CALL": 			{{kTarget}},
+	"RET": 				{{}},
+	"PUSH": 			{{kRegister}},
+	"POP": 				{{kRegister}},
+	"WAIT": 			{{kResource}},
+	"WAIT_TIMEOUT": 	{{kResource, kValue}, {kResource, kValue, kRegister}},
+	"TRY_WAIT": 		{{kResource, kRegister}},
+	"SIGNAL": 			{{kResource}},
+	"SLEEP": 			{{kValue}},
+	"IO_GEN_SLEEP": 	{{kGeneric, kNumber}},
+	"IO_STDIN_READ": 	{{kStdin, kRegister, kRegister}},
+	"IO_STDOUT_WRITE": 	{{kStdout, kRegister, kRegister}},
+	"IO_FS_CREATE": 	{{kDialFS, kFile}},
+	"IO_FS_DELETE": 	{{kDialFS, kFile}},
+	"IO_FS_TRUNCATE": 	{{kDialFS, kFile, kRegister}},
+	"IO_FS_WRITE": 		{{kDialFS, kFile, kRegister, kRegister, kRegister}},
+	"IO_FS_READ": 		{{kDialFS, kFile, kRegister, kRegister, kRegister}},
+}
+
+// Instructions: Instrucciones que entiende CPU, ordenadas
+func Instructions() []string {
+	names := make([]string, 0, len(instructions))
+	for name := range instructions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Instrucción del script, ya sin etiqueta, con su línea de origen
+type sourceInstruction struct {
+	line 	int
+	fields 	[]string
+}
+
+/**
+ * Validate: Valida un script completo y, si no tiene errores, lo ensambla.
+ * A diferencia de assembler.Assemble no se detiene en el primer error: devuelve todos, ordenados por línea.
+ * Lo que puede estar bien al momento de ejecutar (un recurso o interfaz que todavía no existe, cómo termina el script) es advertencia.
+
+ * @param lines: líneas del script
+ * @param ctx: recursos e interfaces conocidos
+ * @return assembler.T_Program: script ensamblado (vacío si hubo errores)
+ * @return []T_Diagnostic: errores y advertencias encontrados
+*/
+func Validate(lines []string, ctx T_Context) (assembler.T_Program, []T_Diagnostic) {
+	var diagnostics []T_Diagnostic
+	report := func(line int, format string, args ...any) {
+		diagnostics = append(diagnostics, T_Diagnostic{Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+	warn := func(line int, format string, args ...any) {
+		diagnostics = append(diagnostics, T_Diagnostic{Line: line, Message: fmt.Sprintf(format, args...), Warning: true})
+	}
+
+	// Primera pasada: etiquetas. Cada línea que no es solo una etiqueta es una instrucción (las vacías no hacen nada).
+	labels := make(map[string]int)
+	var program []sourceInstruction
+	for i, line := range lines {
+		name, rest := assembler.SplitLabel(line)
+		if name != "" {
+			if _, duplicated := labels[name]; duplicated {
+				report(i+1, "etiqueta %q duplicada", name)
+			} else if pcb.IsRegister(name) {
+				report(i+1, "la etiqueta %q tiene el nombre de un registro", name)
+			} else {
+				labels[name] = len(program)
+			}
+			if strings.TrimSpace(rest) == "" {
+				continue
+			}
+		}
+		program = append(program, sourceInstruction{line: i + 1, fields: strings.Fields(rest)})
+	}
+
+	// Segunda pasada: instrucciones
+	v := validation{ctx: ctx, labels: labels, count: len(program), report: report, warn: warn}
+	for _, instruction := range program {
+		v.instruction(instruction)
+	}
+
+	// Pasarse de la última instrucción no tiene sentido, pero un salto condicional que siempre salta también es un final válido
+	for i := len(program) - 1; i >= 0; i-- {
+		if len(program[i].fields) == 0 {
+			continue
+		}
+		if last := program[i].fields[0]; last != "EXIT" && last != "RET" && last != "JMP" {
+			warn(program[i].line, "la última instrucción es %s: si no salta, el proceso se pasa del final del script", last)
+		}
+		break
+	}
+	if len(program) == 0 {
+		report(1, "el script no tiene instrucciones")
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool { return diagnostics[i].Line < diagnostics[j].Line })
+	if HasErrors(diagnostics) {
+		return assembler.T_Program{}, diagnostics
+	}
+
+	assembled, err := assembler.Assemble(lines)
+	if err != nil {
+		return assembler.T_Program{}, append(diagnostics, T_Diagnostic{Line: 0, Message: err.Error()})
+	}
+	return assembled, diagnostics
+}
+
+type validation struct {
+	ctx 		T_Context
+	labels 		map[string]int
+	count 		int
+	assembled 	bool 	// instrucción que ya pasó por el ensamblador: los saltos son números o registros
+	report 		func(line int, format string, args ...any)
+	warn 		func(line int, format string, args ...any) // nil: las advertencias se descartan
+}
+
+// Tipo de falla de una instrucción ya ensamblada
+type FaultKind int
+
+const (
+	FaultInstruction 	FaultKind = iota 	// código de operación desconocido o cantidad de operandos incorrecta
+	FaultRegister 							// un operando nombra un registro que no existe
+)
+
+// Falla de una instrucción ya ensamblada, que CPU convierte en excepción
+type T_Fault struct {
+	Kind 		FaultKind
+	Message 	string
+}
+
+func (f T_Fault) Error() string {
+	return f.Message
+}
+
+/**
+ * CheckInstruction: Valida una instrucción ya ensamblada, tal como la ejecuta CPU. No conoce etiquetas, recursos ni interfaces.
+
+ * @param fields: código de operación y operandos
+ * @return *T_Fault: la primera falla encontrada, o nil si la instrucción es válida
+*/
+func CheckInstruction(fields []string) *T_Fault {
+	var fault *T_Fault
+	v := validation{assembled: true}
+	v.report = func(line int, format string, args ...any) {
+		if fault == nil {
+			fault = &T_Fault{Kind: FaultInstruction, Message: fmt.Sprintf(format, args...)}
+		}
+	}
+
+	if len(fields) == 0 || fields[0] == "" {
+		return &T_Fault{Kind: FaultInstruction, Message: "instrucción vacía"}
+	}
+	v.instruction(sourceInstruction{fields: fields})
+	if fault == nil {
+		return nil
+	}
+
+	// Si el primer operando inválido está donde va un registro y es un nombre, es un registro que no existe
+	for _, form := range instructions[fields[0]] {
+		if len(form) != len(fields)-1 {
+			continue
+		}
+		for i, k := range form {
+			if v.operand(k, fields[i+1]) != "" {
+				if namesRegister(k, fields[i+1]) {
+					fault.Kind = FaultRegister
+				}
+				return fault
+			}
+		}
+	}
+	return fault
+}
+
+// namesRegister: Indica si el operando, en una posición que admite registros, parece un nombre (no un número)
+func namesRegister(k kind, operand string) bool {
+	switch k {
+	case kRegister, kValue, kOperand, kDest, kTarget:
+	default:
+		return false
+	}
+	name := strings.TrimPrefix(operand, "[")
+	if i := strings.IndexAny(name, "+-]"); i >= 0 {
+		name = name[:i]
+	}
+	return name != "" && (name[0] >= 'A' && name[0] <= 'Z' || name[0] >= 'a' && name[0] <= 'z')
+}
+
+// instruction: Valida el código de operación, la cantidad de operandos y cada operando
+func (v validation) instruction(instruction sourceInstruction) {
+	if len(instruction.fields) == 0 {
+		return
+	}
+	opcode, operands := instruction.fields[0], instruction.fields[1:]
+
+	forms, known := instructions[opcode]
+	if !known {
+		v.report(instruction.line, "instrucción %q desconocida", opcode)
+		return
+	}
+
+	var form []kind
+	found := false
+	counts := make([]string, 0, len(forms))
+	for _, candidate := range forms {
+		if len(candidate) == len(operands) {
+			form, found = candidate, true
+		}
+		counts = append(counts, fmt.Sprint(len(candidate)))
+	}
+	if !found {
+		v.report(instruction.line, "%s lleva %s operandos y tiene %d", opcode, strings.Join(counts, " o "), len(operands))
+		return
+	}
+
+	for i, operand := range operands {
+		message := v.operand(form[i], operand)
+		if message == "" {
+			continue
+		}
+		// Un recurso se puede crear y una interfaz conectar después de cargar el script
+		if v.unknownName(form[i], operand) {
+			if v.warn != nil {
+				v.warn(instruction.line, "%s, operando %d: %s", opcode, i+1, message)
+			}
+			continue
+		}
+		v.report(instruction.line, "%s, operando %d: %s", opcode, i+1, message)
+	}
+}
+
+// operand: Devuelve el error del operando según su tipo, o "" si es válido
+func (v validation) operand(k kind, operand string) string {
+	switch k {
+	case kRegister:
+		if !pcb.IsRegister(operand) {
+			return fmt.Sprintf("%q no es un registro", operand)
+		}
+
+	case kValue:
+		if _, err := assembler.ParseNumber(operand); err != nil && !pcb.IsRegister(operand) {
+			return fmt.Sprintf("%q no es un registro ni un número", operand)
+		}
+
+	case kNumber:
+		if _, err := assembler.ParseNumber(operand); err != nil {
+			return fmt.Sprintf("%q no es un número", operand)
+		}
+
+	case kOperand, kDest:
+		parsed, err := assembler.ParseOperand(operand)
+		if err != nil {
+			return err.Error()
+		}
+		if k == kDest && parsed.Kind == assembler.OperandImmediate {
+			return "un inmediato no puede ser destino"
+		}
+
+	case kTarget:
+		if pcb.IsRegister(operand) {
+			return ""
+		}
+		if v.assembled {
+			if _, err := assembler.ParseNumber(operand); err != nil {
+				return fmt.Sprintf("%q no es un registro ni un número de instrucción", operand)
+			}
+			return ""
+		}
+		if _, ok := v.labels[operand]; ok {
+			return ""
+		}
+		target, err := assembler.ParseNumber(operand)
+		if err != nil {
+			return fmt.Sprintf("etiqueta %q no definida", operand)
+		}
+		if int(target) >= v.count {
+			return fmt.Sprintf("salto a la instrucción %d, pero el script tiene %d", target, v.count)
+		}
+
+	case kResource:
+		if v.ctx.Resources != nil && !contains(v.ctx.Resources, operand) {
+			return fmt.Sprintf("recurso %q desconocido", operand)
+		}
+
+	case kGeneric, kStdin, kStdout, kDialFS:
+		if v.ctx.Interfaces == nil {
+			return ""
+		}
+		interfaceType, ok := v.ctx.Interfaces[operand]
+		if !ok {
+			return fmt.Sprintf("interfaz %q desconocida", operand)
+		}
+		if interfaceType != interfaceTypes[k] {
+			return fmt.Sprintf("la interfaz %q es %s y se necesita una %s", operand, interfaceType, interfaceTypes[k])
+		}
+	}
+	return ""
+}
+
+// unknownName: Indica si el operando es un recurso o una interfaz que no está en el contexto (y no una interfaz de otro tipo)
+func (v validation) unknownName(k kind, operand string) bool {
+	switch k {
+	case kResource:
+		return true
+	case kGeneric, kStdin, kStdout, kDialFS:
+		_, known := v.ctx.Interfaces[operand]
+		return !known
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package validator
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func validate(t *testing.T, script string, ctx T_Context) []T_Diagnostic {
+	t.Helper()
+	_, diagnostics := Validate(strings.Split(script, "\n"), ctx)
+	return diagnostics
+}
+
+func errorsOf(diagnostics []T_Diagnostic) []T_Diagnostic {
+	var errors []T_Diagnostic
+	for _, diagnostic := range diagnostics {
+		if !diagnostic.Warning {
+			errors = append(errors, diagnostic)
+		}
+	}
+	return errors
+}
+
+// Terminar con un salto condicional que siempre vuelve atrás es válido: solo se advierte
+func TestValidateConditionalJumpAtTheEndIsAWarning(t *testing.T) {
+	script := "SET AX 1\nloop: SUB AX 1\nCMP AX 0\nJGE loop"
+
+	program, diagnostics := Validate(strings.Split(script, "\n"), T_Context{})
+	if HasErrors(diagnostics) {
+		t.Fatalf("errores inesperados: %v", diagnostics)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Line != 4 {
+		t.Fatalf("se esperaba una advertencia en la línea 4, hubo %v", diagnostics)
+	}
+	if len(program.Instructions) != 4 {
+		t.Fatalf("el script se tendría que ensamblar con 4 instrucciones, tiene %d", len(program.Instructions))
+	}
+}
+
+func TestValidateEndingWithExitHasNoDiagnostics(t *testing.T) {
+	if diagnostics := validate(t, "SET AX 1\nEXIT", T_Context{}); len(diagnostics) != 0 {
+		t.Fatalf("diagnósticos inesperados: %v", diagnostics)
+	}
+}
+
+// Todos los errores se reportan, ordenados por línea
+func TestValidateReportsEveryError(t *testing.T) {
+	script := "FOO AX\nSET AX\nJMP nowhere\na: SET BX 1\na: EXIT"
+
+	errors := errorsOf(validate(t, script, T_Context{}))
+	lines := []int{1, 2, 3, 5}
+	if len(errors) != len(lines) {
+		t.Fatalf("se esperaban %d errores, hubo %v", len(lines), errors)
+	}
+	for i, line := range lines {
+		if errors[i].Line != line {
+			t.Errorf("error %d en la línea %d, se esperaba la %d (%s)", i, errors[i].Line, line, errors[i].Message)
+		}
+	}
+}
+
+// Un recurso o interfaz que todavía no existe se advierte; una interfaz de otro tipo es un error
+func TestValidateUnknownNamesAreWarnings(t *testing.T) {
+	ctx := T_Context{
+		Resources:  []string{"RA"},
+		Interfaces: map[string]string{"TECLADO": "STDIN"},
+	}
+
+	diagnostics := validate(t, "WAIT RB\nIO_GEN_SLEEP Interfaz1 10\nEXIT", ctx)
+	if HasErrors(diagnostics) || len(diagnostics) != 2 {
+		t.Fatalf("se esperaban dos advertencias, hubo %v", diagnostics)
+	}
+
+	errors := errorsOf(validate(t, "IO_GEN_SLEEP TECLADO 10\nEXIT", ctx))
+	if len(errors) != 1 || errors[0].Line != 1 {
+		t.Fatalf("se esperaba un error por el tipo de interfaz, hubo %v", errors)
+	}
+}
+
+func TestCheckInstruction(t *testing.T) {
+	tests := []struct {
+		instruction string
+		fault       bool
+		kind        FaultKind
+	}{
+		{"SET AX 1", false, 0},
+		{"JNZ 3", false, 0},
+		{"FOO AX", true, FaultInstruction},
+		{"SET AX", true, FaultInstruction},
+		{"SET AY 1", true, FaultRegister},
+		{"SUM AX [QX+4]", true, FaultRegister},
+	}
+
+	for _, test := range tests {
+		fault := CheckInstruction(strings.Fields(test.instruction))
+		if (fault != nil) != test.fault {
+			t.Errorf("%s: falla %v, se esperaba %v", test.instruction, fault, test.fault)
+			continue
+		}
+		if fault != nil && fault.Kind != test.kind {
+			t.Errorf("%s: tipo de falla %d, se esperaba %d", test.instruction, fault.Kind, test.kind)
+		}
+	}
+}
+
+// Los scripts de algo-pruebas se cargan con la configuración de kernel que trae el repo, como lo haría memoria
+func TestBundledScriptsHaveNoErrors(t *testing.T) {
+	var kernel struct {
+		Resources []string `json:"resources"`
+	}
+	content, err := os.ReadFile("../../kernel/config_kernel.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(content, &kernel); err != nil {
+		t.Fatal(err)
+	}
+	ctx := T_Context{Resources: kernel.Resources}
+
+	var scripts []string
+	for _, pattern := range []string{"our/*.txt", "preliminares/*", "scripts_memoria/*"} {
+		matches, err := filepath.Glob(filepath.Join("../../algo-pruebas", pattern))
+		if err != nil {
+			t.Fatal(err)
+		}
+		scripts = append(scripts, matches...)
+	}
+	if len(scripts) == 0 {
+		t.Fatal("no se encontraron scripts en algo-pruebas")
+	}
+
+	for _, path := range scripts {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var lines []string
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		file.Close()
+
+		_, diagnostics := Validate(lines, ctx)
+		for _, diagnostic := range errorsOf(diagnostics) {
+			t.Errorf("%s:%d: %s", path, diagnostic.Line, diagnostic.Message)
+		}
+	}
+}